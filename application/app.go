@@ -6,7 +6,11 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/SomeSuperCoder/global-chat/internal/auth"
+	"github.com/SomeSuperCoder/global-chat/internal/moderation"
+	"github.com/SomeSuperCoder/global-chat/repository"
 	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
@@ -30,6 +34,10 @@ func (a *App) Start(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("Failed to load .env: %w", err)
 	}
+	err = auth.CheckTestMode()
+	if err != nil {
+		return fmt.Errorf("refusing to start: %w", err)
+	}
 	// ========== MongoDB ==========
 	connectionString := "mongodb://localhost:27017"
 	a.client, err = mongo.Connect(options.Client().ApplyURI(connectionString))
@@ -47,7 +55,43 @@ func (a *App) Start(ctx context.Context) error {
 	}
 
 	// Get the project database
-	a.db = a.client.Database("hackathonframework")
+	a.db = a.client.Database(auth.DatabaseName())
+
+	// Give legacy teams a unique name key before the index is built
+	clashing, err := repository.NewTeamRepo(a.db).BackfillNameKeys(ctx, moderation.NameKey)
+	if err != nil {
+		return fmt.Errorf("failed to backfill team name keys: %w", err)
+	}
+	for _, team := range clashing {
+		logrus.Warnf("Team %s has a duplicate name %q and needs to be renamed", team.ID.Hex(), team.Name)
+	}
+
+	// Create indexes
+	err = repository.EnsureIndexes(ctx, a.db)
+	if err != nil {
+		return fmt.Errorf("failed to create indexes: %w", err)
+	}
+
+	// Recount the limited sizes
+	err = repository.NewCounterRepo(a.db).Sync(ctx, a.db)
+	if err != nil {
+		return fmt.Errorf("failed to sync counters: %w", err)
+	}
+
+	// Move grades embedded into teams into the scores collection
+	migrated, err := repository.NewScoreRepo(a.db).MigrateTeamGrades(ctx, a.db)
+	if err != nil {
+		return fmt.Errorf("failed to migrate team grades: %w", err)
+	}
+	if migrated > 0 {
+		logrus.Infof("Migrated %d embedded grades into scores", migrated)
+	}
+
+	// The published flag became a judging state
+	err = repository.NewSettingsRepo(a.db).MigrateResultsPublished(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to migrate settings: %w", err)
+	}
 
 	// ========== Load Routes ==========
 	a.router = loadRoutes(a.db)
@@ -6,34 +6,95 @@ import (
 
 	"github.com/SomeSuperCoder/global-chat/handlers"
 	"github.com/SomeSuperCoder/global-chat/internal/middleware"
+	"github.com/SomeSuperCoder/global-chat/internal/notify"
 	"github.com/SomeSuperCoder/global-chat/repository"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 func loadRoutes(db *mongo.Database) http.Handler {
 	mux := http.NewServeMux()
+	notifier := notify.New()
 
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintln(w, "OK")
 	})
-	mux.Handle("GET /me", middleware.AuthMiddleware(handlers.MeHandler, db))
-	mux.Handle("/users/", loadUserRoutes(db))
-	mux.Handle("/teams/", loadTeamRoutes(db))
+	meHandler := &handlers.MeHandler{
+		Repo: repository.NewUserRepo(db),
+	}
+	mux.Handle("GET /me", middleware.AuthMiddleware(meHandler.Get, db))
+	mux.Handle("/auth/", loadAuthRoutes(db))
+	mux.Handle("/audit/", loadAuditRoutes(db))
+	mux.Handle("/users/", loadUserRoutes(db, notifier))
+	mux.Handle("/teams/", loadTeamRoutes(db, notifier))
 	mux.Handle("/cases/", loadCaseRoutes(db))
 	mux.Handle("/events/", loadEventRoutes(db))
 	mux.Handle("/criteria/", loadCriterionRoutes(db))
+	mux.Handle("/settings", loadSettingsRoutes(db))
+	leaderboardRoutes := loadLeaderboardRoutes(db)
+	mux.Handle("/leaderboard", leaderboardRoutes)
+	mux.Handle("/leaderboard/", leaderboardRoutes)
+	mux.Handle("/market/", loadMarketRoutes(db, notifier))
+	mux.Handle("/assignments/", loadAssignmentRoutes(db))
+	mux.Handle("/conflicts/", loadConflictRoutes(db))
+	mux.Handle("/webhooks/", loadWebhookRoutes(db))
+	mux.Handle("/rounds/", loadRoundRoutes(db, notifier))
+	mux.Handle("/judging/", loadJudgingRoutes(db, notifier))
+	mux.Handle("/voting/", loadVotingRoutes(db))
+
+	var handler http.Handler = mux
+	handler = middleware.CORSMiddleware(middleware.CORSConfigFromEnv(), handler)
+	handler = middleware.SecurityHeadersMiddleware(middleware.SecurityHeadersConfigFromEnv(), handler)
+
+	return middleware.LoggerMiddleware(handler)
+}
+
+func loadAuthRoutes(db *mongo.Database) http.Handler {
+	authMux := http.NewServeMux()
+	authHandler := &handlers.AuthHandler{
+		UserRepo:    repository.NewUserRepo(db),
+		SessionRepo: repository.NewSessionRepo(db),
+	}
+
+	authMux.HandleFunc("POST /session", authHandler.CreateSession)
+	authMux.HandleFunc("POST /widget", authHandler.CreateWidgetSession)
+	authMux.HandleFunc("POST /refresh", authHandler.Refresh)
+	authMux.HandleFunc("DELETE /session", authHandler.DeleteSession)
+
+	impersonationHandler := &handlers.ImpersonationHandler{
+		Repo:      repository.NewImpersonationRepo(db),
+		UserRepo:  repository.NewUserRepo(db),
+		AuditRepo: repository.NewAuditRepo(db),
+	}
+	authMux.HandleFunc("POST /impersonation", middleware.AuthMiddleware(impersonationHandler.Start, db))
+	authMux.HandleFunc("DELETE /impersonation/{id}", middleware.AuthMiddleware(impersonationHandler.End, db))
+
+	return http.StripPrefix("/auth", authMux)
+}
+
+func loadAuditRoutes(db *mongo.Database) http.Handler {
+	auditMux := http.NewServeMux()
+	impersonationHandler := &handlers.ImpersonationHandler{
+		Repo:      repository.NewImpersonationRepo(db),
+		UserRepo:  repository.NewUserRepo(db),
+		AuditRepo: repository.NewAuditRepo(db),
+	}
 
-	return middleware.LoggerMiddleware(mux)
+	auditMux.HandleFunc("GET /", middleware.AuthMiddleware(impersonationHandler.GetAudit, db))
+
+	return http.StripPrefix("/audit", auditMux)
 }
 
 func loadCaseRoutes(db *mongo.Database) http.Handler {
 	caseMux := http.NewServeMux()
 	caseHandler := &handlers.CaseHandler{
-		Repo: repository.NewCaseRepo(db),
+		Repo:     repository.NewCaseRepo(db),
+		TeamRepo: repository.NewTeamRepo(db),
+		UserRepo: repository.NewUserRepo(db),
 	}
 
-	caseMux.HandleFunc("GET /", caseHandler.Get)
-	caseMux.HandleFunc("GET /{id}", caseHandler.GetByID)
+	caseMux.HandleFunc("GET /", middleware.OptionalAuthMiddleware(caseHandler.Get, db))
+	caseMux.HandleFunc("GET /{id}", middleware.OptionalAuthMiddleware(caseHandler.GetByID, db))
+	caseMux.HandleFunc("GET /{id}/teams", middleware.OptionalAuthMiddleware(caseHandler.GetTeams, db))
 	caseMux.HandleFunc("POST /", middleware.AuthMiddleware(caseHandler.Create, db))
 	caseMux.HandleFunc("PATCH /{id}", middleware.AuthMiddleware(caseHandler.Update, db))
 	caseMux.HandleFunc("DELETE /{id}", middleware.AuthMiddleware(caseHandler.Delete, db))
@@ -47,8 +108,8 @@ func loadEventRoutes(db *mongo.Database) http.Handler {
 		Repo: repository.NewEventRepo(db),
 	}
 
-	eventMux.HandleFunc("GET /", eventHandler.Get)
-	eventMux.HandleFunc("GET /{id}", eventHandler.GetByID)
+	eventMux.HandleFunc("GET /", middleware.OptionalAuthMiddleware(eventHandler.Get, db))
+	eventMux.HandleFunc("GET /{id}", middleware.OptionalAuthMiddleware(eventHandler.GetByID, db))
 	eventMux.HandleFunc("POST /", middleware.AuthMiddleware(eventHandler.Create, db))
 	eventMux.HandleFunc("PATCH /{id}", middleware.AuthMiddleware(eventHandler.Update, db))
 	eventMux.HandleFunc("DELETE /{id}", middleware.AuthMiddleware(eventHandler.Delete, db))
@@ -59,11 +120,12 @@ func loadEventRoutes(db *mongo.Database) http.Handler {
 func loadCriterionRoutes(db *mongo.Database) http.Handler {
 	criterionMux := http.NewServeMux()
 	criterionHandler := &handlers.CriterionHandler{
-		Repo: repository.NewCriterionRepo(db),
+		Repo:     repository.NewCriterionRepo(db),
+		CaseRepo: repository.NewCaseRepo(db),
 	}
 
-	criterionMux.HandleFunc("GET /", criterionHandler.Get)
-	criterionMux.HandleFunc("GET /{id}", criterionHandler.GetByID)
+	criterionMux.HandleFunc("GET /", middleware.OptionalAuthMiddleware(criterionHandler.Get, db))
+	criterionMux.HandleFunc("GET /{id}", middleware.OptionalAuthMiddleware(criterionHandler.GetByID, db))
 	criterionMux.HandleFunc("POST /", middleware.AuthMiddleware(criterionHandler.Create, db))
 	criterionMux.HandleFunc("PATCH /{id}", middleware.AuthMiddleware(criterionHandler.Update, db))
 	criterionMux.HandleFunc("DELETE /{id}", middleware.AuthMiddleware(criterionHandler.Delete, db))
@@ -71,34 +133,291 @@ func loadCriterionRoutes(db *mongo.Database) http.Handler {
 	return http.StripPrefix("/criteria", criterionMux)
 }
 
-func loadTeamRoutes(db *mongo.Database) http.Handler {
+func loadMarketRoutes(db *mongo.Database, notifier *notify.Notifier) http.Handler {
+	marketMux := http.NewServeMux()
+	marketHandler := newMarketHandler(db, notifier)
+
+	marketMux.HandleFunc("GET /profiles", middleware.OptionalAuthMiddleware(marketHandler.GetProfiles, db))
+	marketMux.HandleFunc("GET /positions", middleware.OptionalAuthMiddleware(marketHandler.GetPositions, db))
+	marketMux.HandleFunc("GET /matches", middleware.AuthMiddleware(marketHandler.GetMatches, db))
+	marketMux.HandleFunc("PUT /profile", middleware.AuthMiddleware(marketHandler.PutProfile, db))
+	marketMux.HandleFunc("DELETE /profile", middleware.AuthMiddleware(marketHandler.DeleteProfile, db))
+	marketMux.HandleFunc("POST /push", middleware.AuthMiddleware(marketHandler.PushSuggestions, db))
+
+	return http.StripPrefix("/market", marketMux)
+}
+
+func newMarketHandler(db *mongo.Database, notifier *notify.Notifier) *handlers.MarketHandler {
+	return &handlers.MarketHandler{
+		ProfileRepo:  repository.NewProfileRepo(db),
+		PositionRepo: repository.NewPositionRepo(db),
+		TeamRepo:     repository.NewTeamRepo(db),
+		UserRepo:     repository.NewUserRepo(db),
+		Notifier:     notifier,
+	}
+}
+
+func loadAssignmentRoutes(db *mongo.Database) http.Handler {
+	assignmentMux := http.NewServeMux()
+	assignmentHandler := &handlers.AssignmentHandler{
+		Repo:         repository.NewAssignmentRepo(db),
+		ConflictRepo: repository.NewConflictRepo(db),
+		TeamRepo:     repository.NewTeamRepo(db),
+		UserRepo:     repository.NewUserRepo(db),
+	}
+
+	assignmentMux.HandleFunc("GET /", middleware.AuthMiddleware(assignmentHandler.Get, db))
+	assignmentMux.HandleFunc("POST /", middleware.AuthMiddleware(assignmentHandler.Create, db))
+	assignmentMux.HandleFunc("POST /generate", middleware.AuthMiddleware(assignmentHandler.Generate, db))
+	assignmentMux.HandleFunc("DELETE /{id}", middleware.AuthMiddleware(assignmentHandler.Delete, db))
+
+	return http.StripPrefix("/assignments", assignmentMux)
+}
+
+func loadConflictRoutes(db *mongo.Database) http.Handler {
+	conflictMux := http.NewServeMux()
+	assignmentHandler := &handlers.AssignmentHandler{
+		Repo:         repository.NewAssignmentRepo(db),
+		ConflictRepo: repository.NewConflictRepo(db),
+		TeamRepo:     repository.NewTeamRepo(db),
+		UserRepo:     repository.NewUserRepo(db),
+	}
+
+	conflictMux.HandleFunc("GET /", middleware.AuthMiddleware(assignmentHandler.GetConflicts, db))
+	conflictMux.HandleFunc("POST /", middleware.AuthMiddleware(assignmentHandler.CreateConflict, db))
+	conflictMux.HandleFunc("DELETE /{id}", middleware.AuthMiddleware(assignmentHandler.DeleteConflict, db))
+
+	return http.StripPrefix("/conflicts", conflictMux)
+}
+
+func loadWebhookRoutes(db *mongo.Database) http.Handler {
+	webhookMux := http.NewServeMux()
+	activityHandler := &handlers.ActivityHandler{
+		CommitRepo:   repository.NewCommitRepo(db),
+		TeamRepo:     repository.NewTeamRepo(db),
+		SettingsRepo: repository.NewSettingsRepo(db),
+	}
+
+	// Authenticated by the provider signature instead of a user session
+	webhookMux.HandleFunc("POST /github", activityHandler.GitHubWebhook)
+	webhookMux.HandleFunc("POST /gitlab", activityHandler.GitLabWebhook)
+
+	return http.StripPrefix("/webhooks", webhookMux)
+}
+
+func loadSettingsRoutes(db *mongo.Database) http.Handler {
+	settingsMux := http.NewServeMux()
+	settingsHandler := &handlers.SettingsHandler{
+		Repo: repository.NewSettingsRepo(db),
+	}
+
+	settingsMux.HandleFunc("GET /settings", middleware.OptionalAuthMiddleware(settingsHandler.Get, db))
+	settingsMux.HandleFunc("PATCH /settings", middleware.AuthMiddleware(settingsHandler.Update, db))
+
+	return settingsMux
+}
+
+func loadLeaderboardRoutes(db *mongo.Database) http.Handler {
+	leaderboardMux := http.NewServeMux()
+	leaderboardHandler := newLeaderboardHandler(db)
+
+	leaderboardMux.HandleFunc("GET /leaderboard", middleware.OptionalAuthMiddleware(leaderboardHandler.Get, db))
+	leaderboardMux.HandleFunc("GET /leaderboard/cases", middleware.OptionalAuthMiddleware(leaderboardHandler.GetByCases, db))
+	leaderboardMux.HandleFunc("GET /leaderboard/compare", middleware.AuthMiddleware(leaderboardHandler.Compare, db))
+
+	return leaderboardMux
+}
+
+func newLeaderboardHandler(db *mongo.Database) *handlers.LeaderboardHandler {
+	return &handlers.LeaderboardHandler{
+		TeamRepo:       repository.NewTeamRepo(db),
+		CriterionRepo:  repository.NewCriterionRepo(db),
+		UserRepo:       repository.NewUserRepo(db),
+		AssignmentRepo: repository.NewAssignmentRepo(db),
+		ScoreRepo:      repository.NewScoreRepo(db),
+		SettingsRepo:   repository.NewSettingsRepo(db),
+		RoundRepo:      repository.NewRoundRepo(db),
+		CaseRepo:       repository.NewCaseRepo(db),
+	}
+}
+
+func loadTeamRoutes(db *mongo.Database, notifier *notify.Notifier) http.Handler {
 	teamMux := http.NewServeMux()
 	teamHandler := &handlers.TeamHandler{
-		TeamRepo: repository.NewTeamRepo(db),
-		UserRepo: repository.NewUserRepo(db),
+		TeamRepo:     repository.NewTeamRepo(db),
+		CaseRepo:     repository.NewCaseRepo(db),
+		UserRepo:     repository.NewUserRepo(db),
+		SessionRepo:  repository.NewSessionRepo(db),
+		SettingsRepo: repository.NewSettingsRepo(db),
+		CounterRepo:  repository.NewCounterRepo(db),
+		Notifier:     notifier,
 	}
 
-	teamMux.HandleFunc("GET /", teamHandler.GetPaged)
-	teamMux.HandleFunc("GET /{id}", teamHandler.GetByID)
-	teamMux.HandleFunc("GET /{id}/members", teamHandler.GetMembers)
+	teamMux.HandleFunc("GET /", middleware.OptionalAuthMiddleware(teamHandler.GetPaged, db))
+	teamMux.HandleFunc("GET /{id}", middleware.OptionalAuthMiddleware(teamHandler.GetByID, db))
+	teamMux.HandleFunc("GET /compliance", middleware.AuthMiddleware(teamHandler.GetCompliance, db))
+	teamMux.HandleFunc("GET /names/pending", middleware.AuthMiddleware(teamHandler.GetPendingNames, db))
+	teamMux.HandleFunc("POST /{id}/name/approve", middleware.AuthMiddleware(teamHandler.ApproveName, db))
+	teamMux.HandleFunc("POST /{id}/name/reject", middleware.AuthMiddleware(teamHandler.RejectName, db))
+	teamMux.HandleFunc("GET /{id}/members", middleware.OptionalAuthMiddleware(teamHandler.GetMembers, db))
 	teamMux.HandleFunc("POST /", middleware.AuthMiddleware(teamHandler.Create, db))
 	teamMux.HandleFunc("PATCH /{id}", middleware.AuthMiddleware(teamHandler.Update, db))
 	teamMux.HandleFunc("DELETE /{id}", middleware.AuthMiddleware(teamHandler.Delete, db))
+	teamMux.HandleFunc("POST /{id}/leave", middleware.AuthMiddleware(teamHandler.Leave, db))
+	teamMux.HandleFunc("POST /{id}/leader", middleware.AuthMiddleware(teamHandler.TransferLeadership, db))
+	teamMux.HandleFunc("DELETE /{id}/members/{userId}", middleware.AuthMiddleware(teamHandler.Kick, db))
+	teamMux.HandleFunc("PUT /{id}/case", middleware.AuthMiddleware(teamHandler.SelectCase, db))
+	teamMux.HandleFunc("DELETE /{id}/case", middleware.AuthMiddleware(teamHandler.ClearCase, db))
+
+	submissionHandler := &handlers.SubmissionHandler{
+		Repo:         repository.NewSubmissionRepo(db),
+		TeamRepo:     repository.NewTeamRepo(db),
+		SettingsRepo: repository.NewSettingsRepo(db),
+	}
+	teamMux.HandleFunc("GET /{id}/submissions", middleware.AuthMiddleware(submissionHandler.GetByTeam, db))
+	teamMux.HandleFunc("GET /{id}/submissions/final", middleware.AuthMiddleware(submissionHandler.GetFinal, db))
+	teamMux.HandleFunc("POST /{id}/submissions", middleware.AuthMiddleware(submissionHandler.Create, db))
+	teamMux.HandleFunc("PUT /{id}/submissions/{submissionId}/final", middleware.AuthMiddleware(submissionHandler.MarkFinal, db))
+
+	scoreHandler := newScoreHandler(db)
+	teamMux.HandleFunc("GET /{id}/scores", middleware.AuthMiddleware(scoreHandler.GetByTeam, db))
+	teamMux.HandleFunc("GET /{id}/scores/sheet", middleware.AuthMiddleware(scoreHandler.GetSheet, db))
+	teamMux.HandleFunc("PUT /{id}/scores/{criterionId}", middleware.AuthMiddleware(scoreHandler.Put, db))
+	teamMux.HandleFunc("DELETE /{id}/scores/{criterionId}", middleware.AuthMiddleware(scoreHandler.Delete, db))
+
+	feedbackHandler := newFeedbackHandler(db)
+	teamMux.HandleFunc("GET /{id}/feedback", middleware.AuthMiddleware(feedbackHandler.GetByTeam, db))
+	teamMux.HandleFunc("GET /{id}/feedback/report", middleware.AuthMiddleware(feedbackHandler.Download, db))
+	teamMux.HandleFunc("PUT /{id}/feedback", middleware.AuthMiddleware(feedbackHandler.Put, db))
+	teamMux.HandleFunc("DELETE /{id}/feedback", middleware.AuthMiddleware(feedbackHandler.Delete, db))
+
+	activityHandler := &handlers.ActivityHandler{
+		CommitRepo:   repository.NewCommitRepo(db),
+		TeamRepo:     repository.NewTeamRepo(db),
+		SettingsRepo: repository.NewSettingsRepo(db),
+	}
+	teamMux.HandleFunc("GET /{id}/activity", middleware.AuthMiddleware(activityHandler.GetByTeam, db))
+
+	marketHandler := newMarketHandler(db, notifier)
+	teamMux.HandleFunc("POST /{id}/positions", middleware.AuthMiddleware(marketHandler.CreatePosition, db))
+	teamMux.HandleFunc("DELETE /{id}/positions/{positionId}", middleware.AuthMiddleware(marketHandler.DeletePosition, db))
 
 	return http.StripPrefix("/teams", teamMux)
 }
 
-func loadUserRoutes(db *mongo.Database) http.Handler {
+func newScoreHandler(db *mongo.Database) *handlers.ScoreHandler {
+	return &handlers.ScoreHandler{
+		Repo:           repository.NewScoreRepo(db),
+		TeamRepo:       repository.NewTeamRepo(db),
+		CriterionRepo:  repository.NewCriterionRepo(db),
+		AssignmentRepo: repository.NewAssignmentRepo(db),
+		RoundRepo:      repository.NewRoundRepo(db),
+		CaseRepo:       repository.NewCaseRepo(db),
+		SettingsRepo:   repository.NewSettingsRepo(db),
+		ConflictRepo:   repository.NewConflictRepo(db),
+	}
+}
+
+func newFeedbackHandler(db *mongo.Database) *handlers.FeedbackHandler {
+	return &handlers.FeedbackHandler{
+		Repo:          repository.NewFeedbackRepo(db),
+		ScoreRepo:     repository.NewScoreRepo(db),
+		CriterionRepo: repository.NewCriterionRepo(db),
+		UserRepo:      repository.NewUserRepo(db),
+		SettingsRepo:  repository.NewSettingsRepo(db),
+		Scores:        newScoreHandler(db),
+	}
+}
+
+func loadUserRoutes(db *mongo.Database, notifier *notify.Notifier) http.Handler {
 	userMux := http.NewServeMux()
 	userHandler := &handlers.UserHandler{
-		Repo: repository.NewUserRepo(db),
+		Repo:         repository.NewUserRepo(db),
+		TeamRepo:     repository.NewTeamRepo(db),
+		SessionRepo:  repository.NewSessionRepo(db),
+		SettingsRepo: repository.NewSettingsRepo(db),
+		CounterRepo:  repository.NewCounterRepo(db),
+		Notifier:     notifier,
 	}
 
-	userMux.HandleFunc("GET /", userHandler.GetPaged)
-	userMux.HandleFunc("GET /{id}", userHandler.GetByID)
-	userMux.HandleFunc("GET /by-name/{username}", userHandler.GetByUsername)
+	userMux.HandleFunc("GET /", middleware.OptionalAuthMiddleware(userHandler.GetPaged, db))
+	userMux.HandleFunc("GET /{id}", middleware.OptionalAuthMiddleware(userHandler.GetByID, db))
+	userMux.HandleFunc("GET /by-name/{username}", middleware.OptionalAuthMiddleware(userHandler.GetByUsername, db))
 	userMux.HandleFunc("PATCH /{id}", middleware.AuthMiddleware(userHandler.Update, db))
 	userMux.HandleFunc("DELETE /{id}", middleware.AuthMiddleware(userHandler.Delete, db))
 
 	return http.StripPrefix("/users", userMux)
 }
+
+func loadRoundRoutes(db *mongo.Database, notifier *notify.Notifier) http.Handler {
+	roundMux := http.NewServeMux()
+	roundHandler := &handlers.RoundHandler{
+		Repo:          repository.NewRoundRepo(db),
+		CriterionRepo: repository.NewCriterionRepo(db),
+		TeamRepo:      repository.NewTeamRepo(db),
+		UserRepo:      repository.NewUserRepo(db),
+		Leaderboard:   newLeaderboardHandler(db),
+		Notifier:      notifier,
+	}
+
+	roundMux.HandleFunc("GET /", middleware.OptionalAuthMiddleware(roundHandler.Get, db))
+	roundMux.HandleFunc("GET /{id}", middleware.OptionalAuthMiddleware(roundHandler.GetByID, db))
+	roundMux.HandleFunc("POST /", middleware.AuthMiddleware(roundHandler.Create, db))
+	roundMux.HandleFunc("PATCH /{id}", middleware.AuthMiddleware(roundHandler.Update, db))
+	roundMux.HandleFunc("DELETE /{id}", middleware.AuthMiddleware(roundHandler.Delete, db))
+	roundMux.HandleFunc("POST /{id}/advance", middleware.AuthMiddleware(roundHandler.Advance, db))
+
+	return http.StripPrefix("/rounds", roundMux)
+}
+
+func loadJudgingRoutes(db *mongo.Database, notifier *notify.Notifier) http.Handler {
+	judgingMux := http.NewServeMux()
+	judgingHandler := &handlers.JudgingHandler{
+		SettingsRepo: repository.NewSettingsRepo(db),
+		TeamRepo:     repository.NewTeamRepo(db),
+		RoundRepo:    repository.NewRoundRepo(db),
+		Leaderboard:  newLeaderboardHandler(db),
+		Feedback:     newFeedbackHandler(db),
+		Notifier:     notifier,
+	}
+
+	judgingMux.HandleFunc("GET /state", judgingHandler.GetState)
+	judgingMux.HandleFunc("PUT /state", middleware.AuthMiddleware(judgingHandler.SetState, db))
+
+	progressHandler := &handlers.ProgressHandler{
+		UserRepo:       repository.NewUserRepo(db),
+		TeamRepo:       repository.NewTeamRepo(db),
+		CriterionRepo:  repository.NewCriterionRepo(db),
+		AssignmentRepo: repository.NewAssignmentRepo(db),
+		CaseRepo:       repository.NewCaseRepo(db),
+		ScoreRepo:      repository.NewScoreRepo(db),
+		RoundRepo:      repository.NewRoundRepo(db),
+		Notifier:       notifier,
+	}
+	judgingMux.HandleFunc("GET /progress", middleware.AuthMiddleware(progressHandler.Get, db))
+	judgingMux.HandleFunc("POST /progress/{id}/nudge", middleware.AuthMiddleware(progressHandler.Nudge, db))
+
+	analyticsHandler := &handlers.AnalyticsHandler{
+		UserRepo:    repository.NewUserRepo(db),
+		Leaderboard: newLeaderboardHandler(db),
+	}
+	judgingMux.HandleFunc("GET /analytics", middleware.AuthMiddleware(analyticsHandler.Get, db))
+
+	return http.StripPrefix("/judging", judgingMux)
+}
+
+func loadVotingRoutes(db *mongo.Database) http.Handler {
+	votingMux := http.NewServeMux()
+	votingHandler := &handlers.VotingHandler{
+		SettingsRepo: repository.NewSettingsRepo(db),
+		TeamRepo:     repository.NewTeamRepo(db),
+		VoteRepo:     repository.NewVoteRepo(db),
+	}
+
+	votingMux.HandleFunc("GET /ballot", middleware.AuthMiddleware(votingHandler.GetBallot, db))
+	votingMux.HandleFunc("POST /votes", middleware.AuthMiddleware(votingHandler.Vote, db))
+	votingMux.HandleFunc("GET /results", middleware.OptionalAuthMiddleware(votingHandler.GetResults, db))
+	votingMux.HandleFunc("GET /audit", middleware.AuthMiddleware(votingHandler.GetAudit, db))
+
+	return http.StripPrefix("/voting", votingMux)
+}
@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/internal/middleware"
+	"github.com/SomeSuperCoder/global-chat/internal/webhook"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const maxWebhookBody = 5 << 20
+
+type ActivityHandler struct {
+	CommitRepo   *repository.CommitRepo
+	TeamRepo     *repository.TeamRepo
+	SettingsRepo *repository.SettingsRepo
+}
+
+type ActivityResponse struct {
+	Team           bson.ObjectID `json:"team"`
+	HackathonStart time.Time     `json:"hackathon_start"`
+	TotalCommits   int           `json:"total_commits"`
+	BeforeStart    int           `json:"before_start"`
+	// Pushed commits the providers didn't list, so they are missing from the timeline
+	UnlistedCommits int                    `json:"unlisted_commits"`
+	TruncatedPushes []models.TruncatedPush `json:"truncated_pushes"`
+	Authors         []AuthorActivity       `json:"authors"`
+	Timeline        []CommitActivity       `json:"timeline"`
+}
+
+type AuthorActivity struct {
+	Author      string    `json:"author"`
+	Email       string    `json:"email"`
+	Commits     int       `json:"commits"`
+	FirstCommit time.Time `json:"first_commit"`
+	LastCommit  time.Time `json:"last_commit"`
+}
+
+type CommitActivity struct {
+	models.Commit
+	// Dated before the hackathon start, possibly pre-written code
+	BeforeStart bool `json:"before_start"`
+}
+
+func (h *ActivityHandler) GitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	// Verify
+	body, ok := readWebhook(w, r, webhook.GitHub)
+	if !ok {
+		return
+	}
+	secret, _ := webhook.Secret(webhook.GitHub)
+	err := webhook.VerifyGitHub(body, r.Header.Get("X-Hub-Signature-256"), secret)
+	if utils.CheckError(w, err, "Failed to verify webhook", http.StatusUnauthorized) {
+		return
+	}
+
+	switch r.Header.Get("X-GitHub-Event") {
+	case "ping":
+		fmt.Fprintf(w, "pong")
+		return
+	case "push":
+	default:
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprintf(w, "Event ignored")
+		return
+	}
+
+	// Parse
+	push, err := webhook.ParseGitHubPush(body)
+	if utils.CheckError(w, err, "Failed to parse push event", http.StatusBadRequest) {
+		return
+	}
+
+	h.recordPush(w, r, push)
+}
+
+func (h *ActivityHandler) GitLabWebhook(w http.ResponseWriter, r *http.Request) {
+	// Verify
+	body, ok := readWebhook(w, r, webhook.GitLab)
+	if !ok {
+		return
+	}
+	secret, _ := webhook.Secret(webhook.GitLab)
+	err := webhook.VerifyGitLab(r.Header.Get("X-Gitlab-Token"), secret)
+	if utils.CheckError(w, err, "Failed to verify webhook", http.StatusUnauthorized) {
+		return
+	}
+
+	if r.Header.Get("X-Gitlab-Event") != "Push Hook" {
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprintf(w, "Event ignored")
+		return
+	}
+
+	// Parse
+	push, err := webhook.ParseGitLabPush(body)
+	if utils.CheckError(w, err, "Failed to parse push event", http.StatusBadRequest) {
+		return
+	}
+
+	h.recordPush(w, r, push)
+}
+
+// readWebhook reads the raw body, which is needed as is to check the signature
+func readWebhook(w http.ResponseWriter, r *http.Request, provider string) ([]byte, bool) {
+	if _, err := webhook.Secret(provider); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return nil, false
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if utils.CheckError(w, err, "Failed to read body", http.StatusBadRequest) {
+		return nil, false
+	}
+
+	return body, true
+}
+
+func (h *ActivityHandler) recordPush(w http.ResponseWriter, r *http.Request, push *webhook.Push) {
+	// Load data
+	teams, err := h.TeamRepo.Find(r.Context())
+	if utils.CheckError(w, err, "Failed to get teams", http.StatusInternalServerError) {
+		return
+	}
+
+	matched, repo := matchRepo(teams, push.RepoURLs)
+	if len(matched) == 0 {
+		// Not an error for the provider, so it doesn't keep retrying
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprintf(w, "Repository is not linked to any team")
+		return
+	}
+	// Crediting one of them would be a guess
+	if len(matched) > 1 {
+		http.Error(w, "Repository is linked to several teams", http.StatusConflict)
+		return
+	}
+	team := matched[0]
+
+	// Do work
+	now := time.Now()
+	commits := make([]models.Commit, 0, len(push.Commits))
+	for _, commit := range push.Commits {
+		commits = append(commits, models.Commit{
+			Team:       team.ID,
+			Provider:   push.Provider,
+			Repo:       repo,
+			Ref:        push.Ref,
+			SHA:        commit.SHA,
+			Message:    commit.Message,
+			URL:        commit.URL,
+			Author:     commit.Author,
+			Email:      commit.Email,
+			Timestamp:  commit.Timestamp,
+			ReceivedAt: now,
+		})
+	}
+
+	recorded, err := h.CommitRepo.Record(r.Context(), commits)
+	if utils.CheckError(w, err, "Failed to record commits", http.StatusInternalServerError) {
+		return
+	}
+
+	if unlisted := push.Unlisted(); unlisted > 0 {
+		err = h.CommitRepo.RecordTruncated(r.Context(), &models.TruncatedPush{
+			Team:       team.ID,
+			Repo:       repo,
+			Ref:        push.Ref,
+			Unlisted:   unlisted,
+			CompareURL: push.CompareURL,
+			ReceivedAt: now,
+		})
+		if utils.CheckError(w, err, "Failed to record the push", http.StatusInternalServerError) {
+			return
+		}
+	}
+
+	// Respond
+	fmt.Fprintf(w, "Recorded %d commits", recorded)
+}
+
+// matchRepo finds the teams which listed one of the repository addresses.
+// Team updates keep repositories unique, more than one match means legacy data.
+func matchRepo(teams []models.Team, urls []string) ([]*models.Team, string) {
+	wanted := make(map[string]bool, len(urls))
+	for _, url := range urls {
+		if url != "" {
+			wanted[webhook.NormalizeRepoURL(url)] = true
+		}
+	}
+
+	var matched []*models.Team
+	var matchedRepo string
+	for i, team := range teams {
+		for _, repo := range team.Repos {
+			if normalized := webhook.NormalizeRepoURL(repo); wanted[normalized] {
+				matched = append(matched, &teams[i])
+				matchedRepo = normalized
+				break
+			}
+		}
+	}
+
+	return matched, matchedRepo
+}
+
+func (h *ActivityHandler) GetByTeam(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	var parsedId bson.ObjectID
+	var exit bool
+	if parsedId, exit = utils.ParseRequestID(w, r); exit {
+		return
+	}
+
+	team, err := h.TeamRepo.GetByID(r.Context(), parsedId)
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+
+	// Check access
+	userAuth := middleware.ExtractUserAuth(r)
+	if userAuth.Role == models.Participant && userAuth.Team != team.ID {
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return
+	}
+
+	settings, err := h.SettingsRepo.Get(r.Context())
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+
+	commits, err := h.CommitRepo.FindByTeam(r.Context(), team.ID)
+	if utils.CheckError(w, err, "Failed to get commits", http.StatusInternalServerError) {
+		return
+	}
+	truncated, err := h.CommitRepo.FindTruncatedByTeam(r.Context(), team.ID)
+	if utils.CheckError(w, err, "Failed to get pushes", http.StatusInternalServerError) {
+		return
+	}
+
+	// Do work
+	response := ActivityResponse{
+		Team:            team.ID,
+		HackathonStart:  settings.HackathonStart,
+		TotalCommits:    len(commits),
+		TruncatedPushes: truncated,
+		Authors:         []AuthorActivity{},
+		Timeline:        make([]CommitActivity, 0, len(commits)),
+	}
+
+	for _, push := range truncated {
+		response.UnlistedCommits += push.Unlisted
+	}
+	response.TotalCommits += response.UnlistedCommits
+
+	authors := make(map[string]*AuthorActivity)
+	for _, commit := range commits {
+		beforeStart := !settings.HackathonStart.IsZero() && commit.Timestamp.Before(settings.HackathonStart)
+		if beforeStart {
+			response.BeforeStart++
+		}
+		response.Timeline = append(response.Timeline, CommitActivity{
+			Commit:      commit,
+			BeforeStart: beforeStart,
+		})
+
+		author, ok := authors[commit.Email]
+		if !ok {
+			author = &AuthorActivity{
+				Author:      commit.Author,
+				Email:       commit.Email,
+				FirstCommit: commit.Timestamp,
+			}
+			authors[commit.Email] = author
+		}
+		author.Commits++
+		author.LastCommit = commit.Timestamp
+	}
+
+	for _, author := range authors {
+		response.Authors = append(response.Authors, *author)
+	}
+	sort.Slice(response.Authors, func(i, j int) bool {
+		return response.Authors[i].Commits > response.Authors[j].Commits
+	})
+
+	// Respond
+	utils.RespondWithJSON(w, response)
+}
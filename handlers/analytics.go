@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/SomeSuperCoder/global-chat/internal/scoring"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type AnalyticsHandler struct {
+	UserRepo    *repository.UserRepo
+	Leaderboard *LeaderboardHandler
+}
+
+type AnalyticsResponse struct {
+	Case      bson.ObjectID `json:"case"`
+	Round     bson.ObjectID `json:"round"`
+	Threshold float64       `json:"threshold"`
+	scoring.Analytics
+}
+
+// Get measures how much the judges agree so disputed scores can be reviewed before publication
+func (h *AnalyticsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Parse
+	threshold := scoring.DefaultOutlierThreshold
+	if value := r.URL.Query().Get("threshold"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 || parsed > 1 {
+			http.Error(w, "Invalid threshold, expected a share of the score range between 0 and 1", http.StatusBadRequest)
+			return
+		}
+		threshold = parsed
+	}
+
+	// Load data
+	data, ok := h.Leaderboard.load(w, r)
+	if !ok {
+		return
+	}
+
+	judgeIDs := make([]bson.ObjectID, 0, len(data.scores))
+	for _, score := range data.scores {
+		judgeIDs = append(judgeIDs, score.Judge)
+	}
+	judges, err := h.UserRepo.FindByIDs(r.Context(), judgeIDs)
+	if utils.CheckError(w, err, "Failed to get judges", http.StatusInternalServerError) {
+		return
+	}
+
+	// Do work
+	analytics := scoring.Analyze(scoring.AnalyticsInput{
+		Teams:     data.teams,
+		Criteria:  data.criteria,
+		Judges:    judges,
+		Grades:    scoring.GradesFromScores(data.scores),
+		Threshold: threshold,
+	})
+
+	// Respond
+	utils.RespondWithJSON(w, AnalyticsResponse{
+		Case:      data.caseID,
+		Round:     roundID(data.round),
+		Threshold: threshold,
+		Analytics: analytics,
+	})
+}
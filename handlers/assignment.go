@@ -0,0 +1,327 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/internal/assignment"
+	"github.com/SomeSuperCoder/global-chat/internal/middleware"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type AssignmentHandler struct {
+	Repo         *repository.AssignmentRepo
+	ConflictRepo *repository.ConflictRepo
+	TeamRepo     *repository.TeamRepo
+	UserRepo     *repository.UserRepo
+}
+
+type GenerateResponse struct {
+	Assignments int `json:"assignments"`
+	assignment.Result
+}
+
+func (h *AssignmentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	// Parse
+	judge, ok := parseOptionalID(w, r, "judge")
+	if !ok {
+		return
+	}
+	team, ok := parseOptionalID(w, r, "team")
+	if !ok {
+		return
+	}
+
+	// Check access
+	userAuth := middleware.ExtractUserAuth(r)
+	switch userAuth.Role {
+	case models.Admin:
+	case models.Judge:
+		// Judges only see their own assignments
+		judge = userAuth.ID
+	default:
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return
+	}
+
+	// Load data
+	assignments, err := h.Repo.FindBy(r.Context(), judge, team)
+	if utils.CheckError(w, err, "Failed to get assignments", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	RespondVisible(w, r, assignments)
+}
+
+// Generate replaces every assignment with a freshly balanced set
+func (h *AssignmentHandler) Generate(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Parse
+	var request struct {
+		PerTeam int  `json:"per_team" validate:"required,min=1"`
+		ByCase  bool `json:"by_case"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	// Load data
+	teams, err := h.TeamRepo.Find(r.Context())
+	if utils.CheckError(w, err, "Failed to get teams", http.StatusInternalServerError) {
+		return
+	}
+
+	users, err := h.UserRepo.Find(r.Context())
+	if utils.CheckError(w, err, "Failed to get users", http.StatusInternalServerError) {
+		return
+	}
+	judges := []models.User{}
+	for _, user := range users {
+		if user.Role == models.Judge {
+			judges = append(judges, user)
+		}
+	}
+	if len(judges) == 0 {
+		http.Error(w, "There are no judges to assign", http.StatusConflict)
+		return
+	}
+
+	conflicts, err := h.ConflictRepo.Find(r.Context())
+	if utils.CheckError(w, err, "Failed to get conflicts", http.StatusInternalServerError) {
+		return
+	}
+
+	// Do work
+	result := assignment.Generate(teams, judges, assignment.Conflicts(conflicts, users), assignment.Options{
+		PerTeam: request.PerTeam,
+		ByCase:  request.ByCase,
+	})
+
+	err = h.Repo.ReplaceAll(r.Context(), result.Assignments)
+	if utils.CheckError(w, err, "Failed to save assignments", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	utils.RespondWithJSON(w, GenerateResponse{
+		Assignments: len(result.Assignments),
+		Result:      result,
+	})
+}
+
+func (h *AssignmentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Parse
+	var request struct {
+		Judge bson.ObjectID `json:"judge" validate:"required"`
+		Team  bson.ObjectID `json:"team" validate:"required"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	// Load data
+	judge, err := h.UserRepo.GetByID(r.Context(), request.Judge)
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+	if judge.Role != models.Judge {
+		http.Error(w, "The user is not a judge", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.TeamRepo.GetByID(r.Context(), request.Team)
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+
+	// Check conflicts
+	conflicted, err := h.isConflicted(r, request.Judge, request.Team)
+	if utils.CheckError(w, err, "Failed to check conflicts", http.StatusInternalServerError) {
+		return
+	}
+	if conflicted {
+		http.Error(w, "The judge has a conflict of interest with the team", http.StatusConflict)
+		return
+	}
+
+	// Do work
+	createdID, err := h.Repo.Create(r.Context(), &models.Assignment{
+		Judge:     request.Judge,
+		Team:      request.Team,
+		CreatedAt: time.Now(),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		http.Error(w, "The judge is already assigned to the team", http.StatusConflict)
+		return
+	} else if utils.CheckError(w, err, "Failed to create", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	fmt.Fprintln(w, createdID.Hex())
+}
+
+func (h *AssignmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	AdminOnlyDelete(w, r, h.Repo)
+}
+
+func (h *AssignmentHandler) GetConflicts(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	userAuth := middleware.ExtractUserAuth(r)
+
+	// Load data
+	var conflicts []models.Conflict
+	var err error
+	switch userAuth.Role {
+	case models.Admin:
+		conflicts, err = h.ConflictRepo.Find(r.Context())
+	case models.Judge:
+		conflicts, err = h.ConflictRepo.FindByJudge(r.Context(), userAuth.ID)
+	default:
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return
+	}
+	if utils.CheckError(w, err, "Failed to get conflicts", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	RespondVisible(w, r, conflicts)
+}
+
+// CreateConflict is used by judges to declare their own conflicts and by admins for any judge
+func (h *AssignmentHandler) CreateConflict(w http.ResponseWriter, r *http.Request) {
+	// Get auth data
+	userAuth := middleware.ExtractUserAuth(r)
+
+	// Parse
+	var request struct {
+		Judge  bson.ObjectID `json:"judge"`
+		Team   bson.ObjectID `json:"team" validate:"required_without=User"`
+		User   bson.ObjectID `json:"user" validate:"required_without=Team,excluded_with=Team"`
+		Reason string        `json:"reason" validate:"required,min=1,max=500"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	// Check access
+	switch userAuth.Role {
+	case models.Admin:
+		if request.Judge.IsZero() {
+			http.Error(w, "JSON validation failed: judge is required", http.StatusBadRequest)
+			return
+		}
+	case models.Judge:
+		if !request.Judge.IsZero() && request.Judge != userAuth.ID {
+			http.Error(w, "Access denied: you can only declare your own conflicts", http.StatusForbidden)
+			return
+		}
+		request.Judge = userAuth.ID
+	default:
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return
+	}
+
+	// Check the referenced documents
+	var teams []bson.ObjectID
+	if !request.Team.IsZero() {
+		_, err := h.TeamRepo.GetByID(r.Context(), request.Team)
+		if utils.CheckGetFromDB(w, err) {
+			return
+		}
+		teams = append(teams, request.Team)
+	} else {
+		user, err := h.UserRepo.GetByID(r.Context(), request.User)
+		if utils.CheckGetFromDB(w, err) {
+			return
+		}
+		teams = append(teams, user.Team)
+	}
+
+	// Do work
+	createdID, err := h.ConflictRepo.Create(r.Context(), &models.Conflict{
+		Judge:     request.Judge,
+		Team:      request.Team,
+		User:      request.User,
+		Reason:    request.Reason,
+		CreatedAt: time.Now(),
+	})
+	if utils.CheckError(w, err, "Failed to create", http.StatusInternalServerError) {
+		return
+	}
+
+	// Existing assignments to the team are dropped right away
+	err = h.Repo.DeleteConflicting(r.Context(), request.Judge, teams)
+	if utils.CheckError(w, err, "Failed to update assignments", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	fmt.Fprintln(w, createdID.Hex())
+}
+
+func (h *AssignmentHandler) DeleteConflict(w http.ResponseWriter, r *http.Request) {
+	Delete(w, r, h.ConflictRepo, func(w http.ResponseWriter, r *http.Request, id bson.ObjectID, userAuth *models.User) bool {
+		conflict, err := h.ConflictRepo.GetByID(r.Context(), id)
+		if utils.CheckGetFromDB(w, err) {
+			return true
+		}
+
+		if userAuth.Role != models.Admin && conflict.Judge != userAuth.ID {
+			http.Error(w, "Access denied", http.StatusForbidden)
+			return true
+		}
+		return false
+	})
+}
+
+func (h *AssignmentHandler) isConflicted(r *http.Request, judge, team bson.ObjectID) (bool, error) {
+	conflicts, err := h.ConflictRepo.FindByJudge(r.Context(), judge)
+	if err != nil {
+		return false, err
+	}
+
+	// Only the judge and the members of the team matter for the pair
+	users, err := h.UserRepo.FindByIDs(r.Context(), []bson.ObjectID{judge})
+	if err != nil {
+		return false, err
+	}
+	members, err := h.TeamRepo.GetMembers(r.Context(), team)
+	if err != nil {
+		return false, err
+	}
+
+	return assignment.Conflicts(conflicts, append(users, members...)).Has(judge, team), nil
+}
+
+// parseOptionalID reads an ObjectID from the query, a missing value is the zero ID
+func parseOptionalID(w http.ResponseWriter, r *http.Request, key string) (bson.ObjectID, bool) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return bson.NilObjectID, true
+	}
+
+	id, err := bson.ObjectIDFromHex(value)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid %s ID", key), http.StatusBadRequest)
+		return bson.NilObjectID, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/internal/auth"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type AuthHandler struct {
+	UserRepo    *repository.UserRepo
+	SessionRepo *repository.SessionRepo
+}
+
+type SessionResponse struct {
+	AccessToken      string    `json:"access_token,omitempty"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshToken     string    `json:"refresh_token,omitempty"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	CSRFToken        string    `json:"csrf_token,omitempty"`
+}
+
+func (h *AuthHandler) CreateSession(w http.ResponseWriter, r *http.Request) {
+	// Validate init data once
+	user, err, code := utils.Authorize(r, h.UserRepo)
+	if err != nil {
+		http.Error(w, fmt.Errorf("Failed to authorize: %w", err).Error(), code)
+		return
+	}
+
+	h.startSession(w, r, user, false)
+}
+
+func (h *AuthHandler) CreateWidgetSession(w http.ResponseWriter, r *http.Request) {
+	// Parse the Login Widget payload, keeping numbers as they were sent
+	var payload map[string]any
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	err := decoder.Decode(&payload)
+	if utils.CheckJSONError(w, err) {
+		return
+	}
+
+	fields := make(map[string]string, len(payload))
+	for key, value := range payload {
+		fields[key] = fmt.Sprint(value)
+	}
+
+	// Validate
+	err = auth.ValidateWidget(fields, os.Getenv("TELEGRAM_TOKEN"), 24*time.Hour)
+	if utils.CheckError(w, err, "Failed to validate widget data", http.StatusBadRequest) {
+		return
+	}
+
+	// Users are matched by username, so accounts without one can't use the widget
+	if fields["username"] == "" {
+		http.Error(w, "Telegram account has no username: set one in Telegram and try again", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.UserRepo.GetByUsername(r.Context(), fields["username"])
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+	if utils.CheckError(w, err, "Failed to load user", http.StatusInternalServerError) {
+		return
+	}
+
+	h.startSession(w, r, user, true)
+}
+
+func (h *AuthHandler) startSession(w http.ResponseWriter, r *http.Request, user *models.User, browser bool) {
+	refreshToken, refreshHash, err := auth.NewRefreshToken()
+	if utils.CheckError(w, err, "Failed to generate refresh token", http.StatusInternalServerError) {
+		return
+	}
+
+	session := &models.Session{
+		User:        user.ID,
+		RefreshHash: refreshHash,
+		CreatedAt:   time.Now(),
+		ExpiresAt:   time.Now().Add(auth.RefreshTokenTTL),
+	}
+	session.ID, err = h.SessionRepo.Create(r.Context(), session)
+	if utils.CheckError(w, err, "Failed to create session", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	h.respondWithSession(w, session, refreshToken, user, browser)
+}
+
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	// Parse
+	refreshToken, browser, ok := parseRefreshToken(w, r)
+	if !ok {
+		return
+	}
+
+	// Load data
+	session, ok := h.loadSession(w, r, refreshToken)
+	if !ok {
+		return
+	}
+
+	user, err := h.UserRepo.GetByID(r.Context(), session.User)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		h.SessionRepo.DeleteByUser(r.Context(), session.User)
+		http.Error(w, "User no longer exists", http.StatusUnauthorized)
+		return
+	} else if utils.CheckError(w, err, "Failed to get user from DB", http.StatusInternalServerError) {
+		return
+	}
+
+	// Rotate the refresh token
+	refreshToken, refreshHash, err := auth.NewRefreshToken()
+	if utils.CheckError(w, err, "Failed to generate refresh token", http.StatusInternalServerError) {
+		return
+	}
+	session.RefreshHash = refreshHash
+	session.ExpiresAt = time.Now().Add(auth.RefreshTokenTTL)
+
+	err = h.SessionRepo.Update(r.Context(), session.ID, bson.M{
+		"refresh_hash": session.RefreshHash,
+		"expires_at":   session.ExpiresAt,
+	})
+	if utils.CheckError(w, err, "Failed to update session", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	h.respondWithSession(w, session, refreshToken, user, browser)
+}
+
+func (h *AuthHandler) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	// Parse
+	refreshToken, browser, ok := parseRefreshToken(w, r)
+	if !ok {
+		return
+	}
+
+	// Load data
+	session, ok := h.loadSession(w, r, refreshToken)
+	if !ok {
+		return
+	}
+
+	// Do work
+	err := h.SessionRepo.Delete(r.Context(), session.ID)
+	if utils.CheckError(w, err, "Failed to delete", http.StatusInternalServerError) {
+		return
+	}
+	if browser {
+		auth.ClearSessionCookies(w)
+	}
+
+	// Respond
+	fmt.Fprintf(w, "Successfully deleted")
+}
+
+func (h *AuthHandler) loadSession(w http.ResponseWriter, r *http.Request, refreshToken string) (*models.Session, bool) {
+	if refreshToken == "" {
+		http.Error(w, "No refresh token provided", http.StatusBadRequest)
+		return nil, false
+	}
+
+	session, err := h.SessionRepo.GetByRefreshHash(r.Context(), auth.HashRefreshToken(refreshToken))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		return nil, false
+	} else if utils.CheckError(w, err, "Failed to get session from DB", http.StatusInternalServerError) {
+		return nil, false
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		h.SessionRepo.Delete(r.Context(), session.ID)
+		http.Error(w, "Refresh token expired", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	return session, true
+}
+
+// parseRefreshToken reads the refresh token from the JSON body or, for browser sessions, from the cookie
+func parseRefreshToken(w http.ResponseWriter, r *http.Request) (string, bool, bool) {
+	if cookie, err := r.Cookie(auth.RefreshCookie); err == nil && cookie.Value != "" {
+		// Cookies are sent automatically, so require the CSRF token as well
+		csrf, err := r.Cookie(auth.CSRFCookie)
+		if err != nil || csrf.Value == "" || r.Header.Get(auth.CSRFHeader) != csrf.Value {
+			http.Error(w, "CSRF token mismatch", http.StatusForbidden)
+			return "", false, false
+		}
+
+		return cookie.Value, true, true
+	}
+
+	var request struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if utils.CheckJSONError(w, err) {
+		return "", false, false
+	}
+
+	return request.RefreshToken, false, true
+}
+
+// respondWithSession returns the tokens in the body, or in cookies for browser sessions
+func (h *AuthHandler) respondWithSession(w http.ResponseWriter, session *models.Session, refreshToken string, user *models.User, browser bool) {
+	claims := auth.NewClaims(session.ID, user)
+	if browser {
+		csrf, err := auth.NewCSRFToken()
+		if utils.CheckError(w, err, "Failed to generate CSRF token", http.StatusInternalServerError) {
+			return
+		}
+		claims.CSRF = csrf
+	}
+
+	accessToken, err := auth.IssueAccessToken(claims)
+	if utils.CheckError(w, err, "Failed to issue access token", http.StatusInternalServerError) {
+		return
+	}
+
+	if !browser {
+		utils.RespondWithJSON(w, SessionResponse{
+			AccessToken:      accessToken,
+			AccessExpiresAt:  claims.ExpiresAt,
+			RefreshToken:     refreshToken,
+			RefreshExpiresAt: session.ExpiresAt,
+		})
+		return
+	}
+
+	auth.SetSessionCookies(w, accessToken, claims.ExpiresAt, refreshToken, session.ExpiresAt, claims.CSRF)
+	utils.RespondWithJSON(w, SessionResponse{
+		AccessExpiresAt:  claims.ExpiresAt,
+		RefreshExpiresAt: session.ExpiresAt,
+		CSRFToken:        claims.CSRF,
+	})
+}
+
+// revokeSessions makes the current claims of a user stale so the role and team are re-read on refresh
+func revokeSessions(ctx context.Context, repo *repository.SessionRepo, userID bson.ObjectID) {
+	if err := auth.RevokeUser(ctx, repo, userID); err != nil {
+		logrus.Errorf("Failed to revoke sessions of %s: %v", userID.Hex(), err)
+	}
+}
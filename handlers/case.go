@@ -3,12 +3,17 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/SomeSuperCoder/global-chat/internal/middleware"
 	"github.com/SomeSuperCoder/global-chat/models"
 	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type CaseHandler struct {
-	Repo *repository.CaseRepo
+	Repo     *repository.CaseRepo
+	TeamRepo *repository.TeamRepo
+	UserRepo *repository.UserRepo
 }
 
 func (h *CaseHandler) Get(w http.ResponseWriter, r *http.Request) {
@@ -19,28 +24,78 @@ func (h *CaseHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	GetByID(w, r, h.Repo)
 }
 
+func (h *CaseHandler) GetTeams(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	var parsedId bson.ObjectID
+	var exit bool
+	if parsedId, exit = utils.ParseRequestID(w, r); exit {
+		return
+	}
+
+	// Do work
+	teams, err := h.TeamRepo.FindByCase(r.Context(), parsedId)
+	if utils.CheckError(w, err, "Failed to get teams", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	RespondVisible(w, r, hideNames(teams, middleware.ExtractOptionalUserAuth(r)))
+}
+
 func (h *CaseHandler) Create(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Parse
 	var request struct {
-		Name        string `json:"name" bson:"name" validate:"required,min=1,max=40"`
-		Description string `json:"description" bson:"description" validate:"required"`
-		ImageURI    string `json:"image_uri" bson:"image_uri" validate:"omitempty,url"`
-	}
-	AdminOnlyCreate(w, r, h.Repo, &request, func() *models.Case {
-		return &models.Case{
-			Name:        request.Name,
-			Description: request.Description,
-			ImageURI:    request.ImageURI,
-		}
+		Name        string          `json:"name" bson:"name" validate:"required,min=1,max=40"`
+		Description string          `json:"description" bson:"description" validate:"required"`
+		ImageURI    string          `json:"image_uri" bson:"image_uri" validate:"omitempty,url"`
+		Capacity    int             `json:"capacity" bson:"capacity" validate:"min=0"`
+		Judges      []bson.ObjectID `json:"judges" bson:"judges"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+	if checkJudges(w, r, h.UserRepo, request.Judges) {
+		return
+	}
+
+	CreateInner(w, r, h.Repo, &models.Case{
+		Name:        request.Name,
+		Description: request.Description,
+		ImageURI:    request.ImageURI,
+		Capacity:    request.Capacity,
+		Judges:      orEmpty(request.Judges),
 	})
 }
 
 func (h *CaseHandler) Update(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	var parsedId bson.ObjectID
+	var exit bool
+	if parsedId, exit = utils.ParseRequestID(w, r); exit {
+		return
+	}
+
+	// Parse
 	var request struct {
-		Name        string `json:"name" bson:"name,omitempty" validate:"omitempty,admin,min=1,max=40"`
-		Description string `json:"description" bson:"description,omitempty" validate:"omitempty,admin"`
-		ImageURI    string `json:"image_uri" bson:"image_uri,omitempty" validate:"omitempty,admin,url"`
+		Name        string           `json:"name" bson:"name,omitempty" validate:"omitempty,admin,min=1,max=40"`
+		Description string           `json:"description" bson:"description,omitempty" validate:"omitempty,admin"`
+		ImageURI    string           `json:"image_uri" bson:"image_uri,omitempty" validate:"omitempty,admin,url"`
+		Capacity    *int             `json:"capacity" bson:"capacity,omitempty" validate:"omitempty,admin,min=0"`
+		Judges      *[]bson.ObjectID `json:"judges" bson:"judges,omitempty" validate:"omitempty,admin"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
 	}
-	Update(w, r, h.Repo, request)
+	if request.Judges != nil && checkJudges(w, r, h.UserRepo, *request.Judges) {
+		return
+	}
+
+	UpdateInner(w, r, h.Repo, parsedId, request)
 }
 
 func (h *CaseHandler) Delete(w http.ResponseWriter, r *http.Request) {
@@ -1,19 +1,38 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/SomeSuperCoder/global-chat/models"
 	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 type CriterionHandler struct {
-	Repo *repository.CriterionRepo
+	Repo     *repository.CriterionRepo
+	CaseRepo *repository.CaseRepo
 }
 
+// Get lists every criterion, or with ?case= the criteria that apply to the teams of the case
 func (h *CriterionHandler) Get(w http.ResponseWriter, r *http.Request) {
-	Get(w, r, h.Repo)
+	caseID, ok := parseOptionalID(w, r, "case")
+	if !ok {
+		return
+	}
+	if caseID.IsZero() {
+		Get(w, r, h.Repo)
+		return
+	}
 
+	criteria, err := h.Repo.Find(r.Context())
+	if utils.CheckError(w, err, "Failed to get from DB", http.StatusInternalServerError) {
+		return
+	}
+
+	RespondVisible(w, r, models.ApplicableCriteria(criteria, caseID))
 }
 
 func (h *CriterionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
@@ -21,23 +40,123 @@ func (h *CriterionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *CriterionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Parse
 	var request struct {
-		Text string `json:"text" bson:"text" validate:"required,min=1,max=40"`
+		Text        string        `json:"text" bson:"text" validate:"required,min=1,max=40"`
+		Description string        `json:"description" bson:"description" validate:"max=1000"`
+		MinScore    int           `json:"min_score" bson:"min_score" validate:"min=0"`
+		MaxScore    *int          `json:"max_score" bson:"max_score" validate:"omitempty,min=1"`
+		Weight      *float64      `json:"weight" bson:"weight" validate:"omitempty,gt=0"`
+		Order       int           `json:"order" bson:"order"`
+		Category    string        `json:"category" bson:"category" validate:"max=40"`
+		Case        bson.ObjectID `json:"case" bson:"case"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
 	}
-	AdminOnlyCreate(w, r, h.Repo, &request, func() *models.Criterion {
-		return &models.Criterion{
-			Text: request.Text,
-		}
-	})
+	if h.checkCase(w, r, request.Case) {
+		return
+	}
+
+	criterion := &models.Criterion{
+		Text:        request.Text,
+		Description: request.Description,
+		MinScore:    request.MinScore,
+		MaxScore:    models.DefaultMaxScore,
+		Weight:      1,
+		Order:       request.Order,
+		Category:    request.Category,
+		Case:        request.Case,
+	}
+	if request.MaxScore != nil {
+		criterion.MaxScore = *request.MaxScore
+	}
+	if request.Weight != nil {
+		criterion.Weight = *request.Weight
+	}
+
+	// Validate the range once the defaults are filled in
+	if criterion.MinScore >= criterion.MaxScore {
+		http.Error(w, "JSON validation failed: min_score must be less than max_score", http.StatusBadRequest)
+		return
+	}
+
+	CreateInner(w, r, h.Repo, criterion)
 }
 
 func (h *CriterionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	var parsedId bson.ObjectID
+	var exit bool
+	if parsedId, exit = utils.ParseRequestID(w, r); exit {
+		return
+	}
+
+	criterion, err := h.Repo.GetByID(r.Context(), parsedId)
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+
+	// Parse
 	var request struct {
-		Text string `json:"text" bson:"text,omitempty" validate:"omitempty,admin,required,min=1,max=40"`
+		Text        string         `json:"text" bson:"text,omitempty" validate:"omitempty,admin,required,min=1,max=40"`
+		Description *string        `json:"description" bson:"description,omitempty" validate:"omitempty,admin,max=1000"`
+		MinScore    *int           `json:"min_score" bson:"min_score,omitempty" validate:"omitempty,admin,min=0"`
+		MaxScore    *int           `json:"max_score" bson:"max_score,omitempty" validate:"omitempty,admin,min=1"`
+		Weight      *float64       `json:"weight" bson:"weight,omitempty" validate:"omitempty,admin,gt=0"`
+		Order       *int           `json:"order" bson:"order,omitempty" validate:"omitempty,admin"`
+		Category    *string        `json:"category" bson:"category,omitempty" validate:"omitempty,admin,max=40"`
+		Case        *bson.ObjectID `json:"case" bson:"case,omitempty" validate:"omitempty,admin"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	// Validate
+	if request.Case != nil && h.checkCase(w, r, *request.Case) {
+		return
+	}
+
+	// Validate the resulting range
+	minScore, maxScore := criterion.ScoreRange()
+	if request.MinScore != nil {
+		minScore = *request.MinScore
+	}
+	if request.MaxScore != nil {
+		maxScore = *request.MaxScore
+	}
+	if minScore >= maxScore {
+		http.Error(w, "JSON validation failed: min_score must be less than max_score", http.StatusBadRequest)
+		return
 	}
-	Update(w, r, h.Repo, request)
+	if request.MinScore != nil || request.MaxScore != nil {
+		// Store both bounds so a legacy criterion doesn't fall back to the default range
+		request.MinScore, request.MaxScore = &minScore, &maxScore
+	}
+
+	UpdateInner(w, r, h.Repo, parsedId, request)
 }
 
 func (h *CriterionHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	AdminOnlyDelete(w, r, h.Repo)
 }
+
+// checkCase makes sure the case of the criterion exists, the zero case makes the criterion global
+func (h *CriterionHandler) checkCase(w http.ResponseWriter, r *http.Request, caseID bson.ObjectID) bool {
+	if caseID.IsZero() {
+		return false
+	}
+
+	_, err := h.CaseRepo.GetByID(r.Context(), caseID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		http.Error(w, "Case not found", http.StatusBadRequest)
+		return true
+	}
+
+	return utils.CheckError(w, err, "Failed to get case from DB", http.StatusInternalServerError)
+}
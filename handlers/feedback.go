@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/SomeSuperCoder/global-chat/internal/feedback"
+	"github.com/SomeSuperCoder/global-chat/internal/middleware"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type FeedbackHandler struct {
+	Repo          *repository.FeedbackRepo
+	ScoreRepo     *repository.ScoreRepo
+	CriterionRepo *repository.CriterionRepo
+	UserRepo      *repository.UserRepo
+	SettingsRepo  *repository.SettingsRepo
+	// Scores shares the judge checks of the score endpoints
+	Scores *ScoreHandler
+}
+
+// Put sets the overall feedback of the judge for the team
+func (h *FeedbackHandler) Put(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	team, ok := h.Scores.loadTeam(w, r)
+	if !ok {
+		return
+	}
+	round, ok := h.Scores.loadRound(w, r)
+	if !ok {
+		return
+	}
+
+	// Check access
+	userAuth := middleware.ExtractUserAuth(r)
+	if h.Scores.judgeCheck(w, r, userAuth, team, round) {
+		return
+	}
+
+	// Parse
+	var request struct {
+		Text   string `json:"text" validate:"required,max=5000"`
+		Signed bool   `json:"signed"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	// Do work
+	feedback, err := h.Repo.Upsert(r.Context(), &models.Feedback{
+		Judge:  userAuth.ID,
+		Team:   team.ID,
+		Round:  roundID(round),
+		Text:   request.Text,
+		Signed: request.Signed,
+	})
+	if utils.CheckError(w, err, "Failed to save feedback", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	RespondVisible(w, r, feedback)
+}
+
+func (h *FeedbackHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	team, ok := h.Scores.loadTeam(w, r)
+	if !ok {
+		return
+	}
+	round, ok := h.Scores.loadRound(w, r)
+	if !ok {
+		return
+	}
+
+	// Check access
+	userAuth := middleware.ExtractUserAuth(r)
+	if h.Scores.judgeCheck(w, r, userAuth, team, round) {
+		return
+	}
+
+	// Do work
+	deleted, err := h.Repo.DeleteOne(r.Context(), roundID(round), userAuth.ID, team.ID)
+	if utils.CheckError(w, err, "Failed to delete", http.StatusInternalServerError) {
+		return
+	}
+	if !deleted {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	// Respond
+	fmt.Fprintf(w, "Successfully deleted")
+}
+
+// GetByTeam returns the feedback report of the team
+func (h *FeedbackHandler) GetByTeam(w http.ResponseWriter, r *http.Request) {
+	report, ok := h.loadReport(w, r)
+	if !ok {
+		return
+	}
+
+	utils.RespondWithJSON(w, report)
+}
+
+// Download returns the feedback report of the team as a text file
+func (h *FeedbackHandler) Download(w http.ResponseWriter, r *http.Request) {
+	report, ok := h.loadReport(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="feedback-%s.txt"`, report.Team.Hex()))
+	fmt.Fprint(w, report.Text())
+}
+
+// loadReport shows admins every comment with the judge names, judges their own
+// comments and the team the anonymized comments once the results are published
+func (h *FeedbackHandler) loadReport(w http.ResponseWriter, r *http.Request) (*feedback.Report, bool) {
+	// Load data
+	team, ok := h.Scores.loadTeam(w, r)
+	if !ok {
+		return nil, false
+	}
+	round, ok := h.Scores.loadRound(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	// Check access
+	userAuth := middleware.ExtractUserAuth(r)
+	var judge bson.ObjectID
+	reveal := false
+	switch userAuth.Role {
+	case models.Admin:
+		reveal = true
+	case models.Judge:
+		judge = userAuth.ID
+		reveal = true
+	default:
+		if userAuth.Team != team.ID {
+			http.Error(w, "Access denied", http.StatusForbidden)
+			return nil, false
+		}
+		settings, err := h.SettingsRepo.Get(r.Context())
+		if utils.CheckGetFromDB(w, err) {
+			return nil, false
+		}
+		if !settings.ResultsPublished() {
+			http.Error(w, "Access denied: the results are not published yet", http.StatusForbidden)
+			return nil, false
+		}
+	}
+
+	// Do work
+	report, err := h.report(r.Context(), team, round, judge, reveal)
+	if utils.CheckError(w, err, "Failed to build the feedback report", http.StatusInternalServerError) {
+		return nil, false
+	}
+
+	return report, true
+}
+
+// report builds the feedback of the team in the round, a zero judge includes every judge
+func (h *FeedbackHandler) report(ctx context.Context, team *models.Team, round *models.Round, judge bson.ObjectID, reveal bool) (*feedback.Report, error) {
+	var criteria []models.Criterion
+	var err error
+	if round == nil {
+		criteria, err = h.CriterionRepo.Find(ctx)
+	} else {
+		criteria, err = h.CriterionRepo.FindByIDs(ctx, round.Criteria)
+	}
+	if err != nil {
+		return nil, err
+	}
+	criteria = models.ApplicableCriteria(criteria, team.Case)
+
+	scores, err := h.ScoreRepo.FindBy(ctx, roundID(round), judge, team.ID)
+	if err != nil {
+		return nil, err
+	}
+	feedbacks, err := h.Repo.FindBy(ctx, roundID(round), judge, team.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	judgeIDs := []bson.ObjectID{}
+	for _, f := range feedbacks {
+		judgeIDs = append(judgeIDs, f.Judge)
+	}
+	for _, score := range scores {
+		judgeIDs = append(judgeIDs, score.Judge)
+	}
+	judges, err := h.UserRepo.FindByIDs(ctx, judgeIDs)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[bson.ObjectID]string, len(judges))
+	for _, user := range judges {
+		names[user.ID] = user.Name
+	}
+
+	report := feedback.Build(feedback.Input{
+		Team:     *team,
+		Criteria: criteria,
+		Scores:   scores,
+		Feedback: feedbacks,
+		Judges:   names,
+		Reveal:   reveal,
+	})
+	return &report, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/SomeSuperCoder/global-chat/internal/middleware"
 	"github.com/SomeSuperCoder/global-chat/internal/validators"
+	"github.com/SomeSuperCoder/global-chat/internal/visibility"
 	"github.com/SomeSuperCoder/global-chat/models"
 	"github.com/SomeSuperCoder/global-chat/utils"
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -30,7 +31,7 @@ func GetByID[T any](w http.ResponseWriter, r *http.Request, repo GetteryID[T]) {
 		return
 	}
 
-	utils.RespondWithJSON(w, value)
+	RespondVisible(w, r, value)
 }
 
 // ====================
@@ -44,7 +45,7 @@ func Get[T any](w http.ResponseWriter, r *http.Request, repo Finder[T]) {
 		return
 	}
 
-	utils.RespondWithJSON(w, cases)
+	RespondVisible(w, r, cases)
 }
 
 // ====================
@@ -54,6 +55,13 @@ type PagedFinder[T any] interface {
 	FindPaged(ctx context.Context, page, limit int64) ([]T, int64, error)
 }
 
+// PagedFinderFunc lets a filtered repo method be used as a PagedFinder
+type PagedFinderFunc[T any] func(ctx context.Context, page, limit int64) ([]T, int64, error)
+
+func (f PagedFinderFunc[T]) FindPaged(ctx context.Context, page, limit int64) ([]T, int64, error) {
+	return f(ctx, page, limit)
+}
+
 func FindPaged[T any](w http.ResponseWriter, r *http.Request, repo PagedFinder[T], pagedResponseBuilder PagedResponseBuilder[T]) {
 	// Get data
 	page := r.URL.Query().Get("page")
@@ -87,7 +95,7 @@ func FindPaged[T any](w http.ResponseWriter, r *http.Request, repo PagedFinder[T
 	}
 
 	// Respond
-	utils.RespondWithJSON(w, pagedResponseBuilder(teams, totalCount))
+	RespondVisible(w, r, pagedResponseBuilder(teams, totalCount))
 }
 
 // ====================
@@ -217,6 +225,11 @@ func UpdateInner(w http.ResponseWriter, r *http.Request, repo Updater, id bson.O
 	fmt.Fprintf(w, "Successfully updated")
 }
 
+// RespondVisible hides the fields the caller is not allowed to see
+func RespondVisible(w http.ResponseWriter, r *http.Request, value any) {
+	utils.RespondWithJSON(w, visibility.Filter(value, middleware.ExtractOptionalUserAuth(r)))
+}
+
 func AdminCheck(w http.ResponseWriter, r *http.Request) bool {
 	if middleware.ExtractUserAuth(r).Role != models.Admin {
 		http.Error(w, "Access denied: only the admin can perform this operation", http.StatusForbidden)
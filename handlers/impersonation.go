@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/internal/auth"
+	"github.com/SomeSuperCoder/global-chat/internal/middleware"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type ImpersonationHandler struct {
+	Repo      *repository.ImpersonationRepo
+	UserRepo  *repository.UserRepo
+	AuditRepo *repository.AuditRepo
+}
+
+type GrantResponse struct {
+	ID        bson.ObjectID `json:"_id"`
+	Grant     string        `json:"grant"`
+	ExpiresAt time.Time     `json:"expires_at"`
+}
+
+type AuditResponse struct {
+	Entries    []models.AuditEntry `json:"entries"`
+	TotalCount int64               `json:"count"`
+}
+
+func (h *ImpersonationHandler) Start(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+	if middleware.ExtractActor(r) != nil {
+		http.Error(w, "Access denied: already impersonating", http.StatusForbidden)
+		return
+	}
+
+	// Get auth data
+	userAuth := middleware.ExtractUserAuth(r)
+
+	// Parse
+	var request struct {
+		UserID bson.ObjectID `json:"user_id" validate:"required"`
+		Reason string        `json:"reason" validate:"required,min=1,max=200"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	if request.UserID == userAuth.ID {
+		http.Error(w, "You can't impersonate yourself", http.StatusBadRequest)
+		return
+	}
+
+	// Check if target exists
+	_, err := h.UserRepo.GetByID(r.Context(), request.UserID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		http.Error(w, "Target user not found", http.StatusNotFound)
+		return
+	} else if utils.CheckError(w, err, "Failed to get user from DB", http.StatusInternalServerError) {
+		return
+	}
+
+	// Do work
+	grant := &models.ImpersonationGrant{
+		Admin:     userAuth.ID,
+		Target:    request.UserID,
+		Reason:    request.Reason,
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(auth.ImpersonationTTL),
+	}
+	grant.ID, err = h.Repo.Create(r.Context(), grant)
+	if utils.CheckError(w, err, "Failed to create", http.StatusInternalServerError) {
+		return
+	}
+
+	token, err := auth.IssueGrant(&auth.GrantClaims{
+		GrantID:   grant.ID,
+		Admin:     grant.Admin,
+		Target:    grant.Target,
+		ExpiresAt: grant.ExpiresAt,
+	})
+	if utils.CheckError(w, err, "Failed to issue grant", http.StatusInternalServerError) {
+		return
+	}
+
+	middleware.RecordAudit(r.Context(), h.AuditRepo, &models.AuditEntry{
+		Actor:   grant.Admin,
+		Subject: grant.Target,
+		Grant:   grant.ID,
+		Action:  fmt.Sprintf("impersonation started: %s", grant.Reason),
+		Status:  http.StatusOK,
+	})
+
+	// Respond
+	utils.RespondWithJSON(w, GrantResponse{
+		ID:        grant.ID,
+		Grant:     token,
+		ExpiresAt: grant.ExpiresAt,
+	})
+}
+
+func (h *ImpersonationHandler) End(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	var parsedId bson.ObjectID
+	var exit bool
+	if parsedId, exit = utils.ParseRequestID(w, r); exit {
+		return
+	}
+
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	grant, err := h.Repo.GetByID(r.Context(), parsedId)
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+
+	// Do work
+	err = h.Repo.Update(r.Context(), parsedId, bson.M{
+		"revoked": true,
+	})
+	if utils.CheckError(w, err, "Failed to update", http.StatusInternalServerError) {
+		return
+	}
+
+	middleware.RecordAudit(r.Context(), h.AuditRepo, &models.AuditEntry{
+		Actor:   middleware.ExtractUserAuth(r).ID,
+		Subject: grant.Target,
+		Grant:   grant.ID,
+		Action:  "impersonation ended",
+		Status:  http.StatusOK,
+	})
+
+	// Respond
+	fmt.Fprintf(w, "Successfully ended")
+}
+
+func (h *ImpersonationHandler) GetAudit(w http.ResponseWriter, r *http.Request) {
+	if AdminCheck(w, r) {
+		return
+	}
+
+	FindPaged(w, r, h.AuditRepo, func(values []models.AuditEntry, totalCount int64) any {
+		return AuditResponse{
+			Entries:    values,
+			TotalCount: totalCount,
+		}
+	})
+}
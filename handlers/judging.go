@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/internal/notify"
+	"github.com/SomeSuperCoder/global-chat/internal/scoring"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type JudgingHandler struct {
+	SettingsRepo *repository.SettingsRepo
+	TeamRepo     *repository.TeamRepo
+	RoundRepo    *repository.RoundRepo
+	Leaderboard  *LeaderboardHandler
+	Feedback     *FeedbackHandler
+	Notifier     *notify.Notifier
+}
+
+type JudgingStateResponse struct {
+	State models.JudgingState `json:"state"`
+}
+
+func (h *JudgingHandler) GetState(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	settings, err := h.SettingsRepo.Get(r.Context())
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+
+	// Respond
+	utils.RespondWithJSON(w, JudgingStateResponse{
+		State: settings.JudgingState,
+	})
+}
+
+// SetState moves judging through open, frozen and published, publishing sends the teams their results
+func (h *JudgingHandler) SetState(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Parse
+	var request struct {
+		State models.JudgingState `json:"state" validate:"required,oneof=open frozen published"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	// Load data
+	settings, err := h.SettingsRepo.Get(r.Context())
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+
+	// Validate
+	if !settings.JudgingState.CanMoveTo(request.State) {
+		http.Error(w, fmt.Sprintf("Judging can't go from %s to %s", settings.JudgingState, request.State), http.StatusConflict)
+		return
+	}
+
+	// Do work
+	changed, err := h.SettingsRepo.SetJudgingState(r.Context(), settings.JudgingState, request.State)
+	if utils.CheckError(w, err, "Failed to update", http.StatusInternalServerError) {
+		return
+	}
+	if !changed {
+		http.Error(w, "The judging state was changed by another request", http.StatusConflict)
+		return
+	}
+
+	// Teams get their results once, in the background so a large event doesn't hold the response
+	if request.State == models.JudgingPublished {
+		go h.notifyResults(context.WithoutCancel(r.Context()))
+	}
+
+	// Respond
+	utils.RespondWithJSON(w, JudgingStateResponse{
+		State: request.State,
+	})
+}
+
+// notifyResults sends every team its place and total on the overall leaderboard followed by the feedback of the judges.
+// Each team is marked when its message goes out, so publishing again only reaches the teams a failed run missed.
+func (h *JudgingHandler) notifyResults(ctx context.Context) {
+	data, err := h.Leaderboard.loadData(ctx, bson.NilObjectID, nil)
+	if err != nil {
+		logrus.Errorf("Failed to send the results: %v", err)
+		return
+	}
+	standings, err := data.rank(data.method())
+	if err != nil {
+		logrus.Errorf("Failed to send the results: %v", err)
+		return
+	}
+
+	rounds, err := h.RoundRepo.Find(ctx)
+	if err != nil {
+		logrus.Errorf("Failed to send the results: %v", err)
+		return
+	}
+
+	teams := make(map[bson.ObjectID]*models.Team, len(data.teams))
+	for i := range data.teams {
+		teams[data.teams[i].ID] = &data.teams[i]
+	}
+
+	for _, standing := range standings {
+		team := teams[standing.Team]
+		if !team.ResultsNotifiedAt.IsZero() {
+			continue
+		}
+		if err := h.notifyTeam(ctx, team, standing, len(standings), rounds); err != nil {
+			logrus.Errorf("Failed to send the results to team %s: %v", team.ID.Hex(), err)
+		}
+	}
+}
+
+func (h *JudgingHandler) notifyTeam(ctx context.Context, team *models.Team, standing scoring.Standing, total int, rounds []models.Round) error {
+	members, err := h.TeamRepo.GetMembers(ctx, team.ID)
+	if err != nil || len(members) == 0 {
+		return err
+	}
+	// Members see their own name even while it waits for approval
+	named := *team
+	named.Name = team.NameFor(&members[0])
+
+	text := fmt.Sprintf(
+		"Результаты хакатона опубликованы!\nКоманда «%s» заняла %d место из %d, итоговый балл: %.2f",
+		named.Name, standing.Rank, total, standing.Total,
+	)
+
+	// Feedback left outside of rounds followed by the feedback of every round the team took part in
+	report, err := h.Feedback.report(ctx, &named, nil, bson.NilObjectID, false)
+	if err != nil {
+		return err
+	}
+	if !report.IsEmpty() {
+		text += "\n\n" + report.Text()
+	}
+	for i := range rounds {
+		if !rounds[i].HasTeam(team.ID) {
+			continue
+		}
+		report, err := h.Feedback.report(ctx, &named, &rounds[i], bson.NilObjectID, false)
+		if err != nil {
+			return err
+		}
+		if !report.IsEmpty() {
+			text += fmt.Sprintf("\n\nРаунд «%s»\n", rounds[i].Name) + report.Text()
+		}
+	}
+
+	// Another run may have reached the team meanwhile
+	first, err := h.TeamRepo.MarkResultsNotified(ctx, team.ID, time.Now())
+	if err != nil || !first {
+		return err
+	}
+	h.Notifier.SendToUsers(members, text)
+
+	return nil
+}
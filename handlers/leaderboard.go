@@ -0,0 +1,432 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/SomeSuperCoder/global-chat/internal/middleware"
+	"github.com/SomeSuperCoder/global-chat/internal/scoring"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type LeaderboardHandler struct {
+	TeamRepo       *repository.TeamRepo
+	CriterionRepo  *repository.CriterionRepo
+	UserRepo       *repository.UserRepo
+	AssignmentRepo *repository.AssignmentRepo
+	ScoreRepo      *repository.ScoreRepo
+	RoundRepo      *repository.RoundRepo
+	CaseRepo       *repository.CaseRepo
+	SettingsRepo   *repository.SettingsRepo
+}
+
+type LeaderboardResponse struct {
+	Case      bson.ObjectID      `json:"case"`
+	Round     bson.ObjectID      `json:"round"`
+	Published bool               `json:"published"`
+	Method    string             `json:"method"`
+	TieBreaks []string           `json:"tie_breaks"`
+	Criteria  []models.Criterion `json:"criteria"`
+	Standings []scoring.Standing `json:"standings"`
+}
+
+type CasesLeaderboardResponse struct {
+	Round     bson.ObjectID     `json:"round"`
+	Published bool              `json:"published"`
+	Method    string            `json:"method"`
+	Cases     []CaseLeaderboard `json:"cases"`
+}
+
+// CaseLeaderboard decides the prize of the case
+type CaseLeaderboard struct {
+	Case      bson.ObjectID      `json:"case"`
+	Name      string             `json:"name"`
+	Criteria  []models.Criterion `json:"criteria"`
+	Standings []scoring.Standing `json:"standings"`
+}
+
+type ComparisonResponse struct {
+	Case    bson.ObjectID  `json:"case"`
+	Round   bson.ObjectID  `json:"round"`
+	Methods []string       `json:"methods"`
+	Teams   []ComparedTeam `json:"teams"`
+}
+
+// ComparedTeam holds the placement of a team under every method, keyed by the method
+type ComparedTeam struct {
+	Team    bson.ObjectID            `json:"team"`
+	Name    string                   `json:"name"`
+	Results map[string]ComparedScore `json:"results"`
+}
+
+type ComparedScore struct {
+	Rank  int     `json:"rank"`
+	Total float64 `json:"total"`
+	// Places gained compared to the raw ranking
+	RankChange int `json:"rank_change"`
+}
+
+// leaderboardData is everything a ranking needs, loaded once per request
+type leaderboardData struct {
+	caseID     bson.ObjectID
+	round      *models.Round
+	settings   *models.Settings
+	teams      []models.Team
+	criteria   []models.Criterion
+	scores     []models.Score
+	judgeCount int
+	assigned   map[bson.ObjectID]int
+	tieBreaks  []scoring.TieBreak
+}
+
+func (h *LeaderboardHandler) Get(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	data, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	// Check access
+	if publishedCheck(w, r, data.settings) {
+		return
+	}
+
+	// Do work
+	method := data.method()
+	standings, err := data.rank(method)
+	if utils.CheckError(w, err, "Failed to rank teams", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	utils.RespondWithJSON(w, LeaderboardResponse{
+		Case:      data.caseID,
+		Round:     roundID(data.round),
+		Published: data.settings.ResultsPublished(),
+		Method:    method,
+		TieBreaks: data.settings.TieBreaks,
+		Criteria:  data.criteria,
+		Standings: data.hideNames(standings, middleware.ExtractOptionalUserAuth(r)),
+	})
+}
+
+// GetByCases ranks the teams of every case on the global criteria and the criteria of the case
+func (h *LeaderboardHandler) GetByCases(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	round, ok := h.loadRound(w, r)
+	if !ok {
+		return
+	}
+	settings, err := h.SettingsRepo.Get(r.Context())
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+
+	// Check access
+	if publishedCheck(w, r, settings) {
+		return
+	}
+
+	// Do work
+	cases, err := h.CaseRepo.Find(r.Context())
+	if utils.CheckError(w, err, "Failed to get cases", http.StatusInternalServerError) {
+		return
+	}
+
+	response := CasesLeaderboardResponse{
+		Round:     roundID(round),
+		Published: settings.ResultsPublished(),
+		Cases:     make([]CaseLeaderboard, 0, len(cases)),
+	}
+	for _, c := range cases {
+		data, err := h.loadData(r.Context(), c.ID, round)
+		if utils.CheckError(w, err, "Failed to load the leaderboard", http.StatusInternalServerError) {
+			return
+		}
+		response.Method = data.method()
+		standings, err := data.rank(response.Method)
+		if utils.CheckError(w, err, "Failed to rank teams", http.StatusInternalServerError) {
+			return
+		}
+
+		response.Cases = append(response.Cases, CaseLeaderboard{
+			Case:      c.ID,
+			Name:      c.Name,
+			Criteria:  data.criteria,
+			Standings: data.hideNames(standings, middleware.ExtractOptionalUserAuth(r)),
+		})
+	}
+
+	// Respond
+	utils.RespondWithJSON(w, response)
+}
+
+// Compare ranks the teams with several normalization methods side by side
+func (h *LeaderboardHandler) Compare(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Parse
+	methods := scoring.Methods
+	if value := r.URL.Query().Get("methods"); value != "" {
+		methods = []string{scoring.MethodRaw}
+		for _, method := range strings.Split(value, ",") {
+			method = strings.TrimSpace(method)
+			if !scoring.ValidMethod(method) {
+				http.Error(w, "Unknown normalization method: "+method, http.StatusBadRequest)
+				return
+			}
+			if method != scoring.MethodRaw {
+				methods = append(methods, method)
+			}
+		}
+	}
+
+	// Load data
+	data, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	// Do work
+	response := ComparisonResponse{
+		Case:    data.caseID,
+		Round:   roundID(data.round),
+		Methods: methods,
+		Teams:   make([]ComparedTeam, 0, len(data.teams)),
+	}
+
+	// The raw ranking comes first and sets the order of the response
+	raw, err := data.rank(scoring.MethodRaw)
+	if utils.CheckError(w, err, "Failed to rank teams", http.StatusInternalServerError) {
+		return
+	}
+
+	teams := make(map[bson.ObjectID]*ComparedTeam, len(raw))
+	rawRanks := make(map[bson.ObjectID]int, len(raw))
+	for _, standing := range raw {
+		rawRanks[standing.Team] = standing.Rank
+		response.Teams = append(response.Teams, ComparedTeam{
+			Team:    standing.Team,
+			Name:    standing.Name,
+			Results: make(map[string]ComparedScore, len(methods)),
+		})
+	}
+	for i := range response.Teams {
+		teams[response.Teams[i].Team] = &response.Teams[i]
+	}
+
+	for _, method := range methods {
+		standings, err := data.rank(method)
+		if utils.CheckError(w, err, "Failed to rank teams", http.StatusInternalServerError) {
+			return
+		}
+
+		for _, standing := range standings {
+			teams[standing.Team].Results[method] = ComparedScore{
+				Rank:       standing.Rank,
+				Total:      standing.Total,
+				RankChange: rawRanks[standing.Team] - standing.Rank,
+			}
+		}
+	}
+
+	// Respond
+	utils.RespondWithJSON(w, response)
+}
+
+func (h *LeaderboardHandler) load(w http.ResponseWriter, r *http.Request) (*leaderboardData, bool) {
+	// Parse
+	caseID, ok := parseOptionalID(w, r, "case")
+	if !ok {
+		return nil, false
+	}
+	round, ok := h.loadRound(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	// Load data
+	data, err := h.loadData(r.Context(), caseID, round)
+	if utils.CheckError(w, err, "Failed to load the leaderboard", http.StatusInternalServerError) {
+		return nil, false
+	}
+
+	return data, true
+}
+
+// loadRound reads the optional round query parameter, nil means scores given outside of rounds
+func (h *LeaderboardHandler) loadRound(w http.ResponseWriter, r *http.Request) (*models.Round, bool) {
+	id, ok := parseOptionalID(w, r, "round")
+	if !ok || id.IsZero() {
+		return nil, ok
+	}
+
+	round, err := h.RoundRepo.GetByID(r.Context(), id)
+	if utils.CheckGetFromDB(w, err) {
+		return nil, false
+	}
+
+	return round, true
+}
+
+// loadData collects the teams, criteria and scores of the case and round, zero values select everything outside of rounds
+func (h *LeaderboardHandler) loadData(ctx context.Context, caseID bson.ObjectID, round *models.Round) (*leaderboardData, error) {
+	data := &leaderboardData{
+		caseID: caseID,
+		round:  round,
+	}
+
+	var err error
+	data.settings, err = h.SettingsRepo.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if caseID.IsZero() {
+		data.teams, err = h.TeamRepo.Find(ctx)
+	} else {
+		data.teams, err = h.TeamRepo.FindByCase(ctx, caseID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if round == nil {
+		data.criteria, err = h.CriterionRepo.Find(ctx)
+	} else {
+		data.criteria, err = h.CriterionRepo.FindByIDs(ctx, round.Criteria)
+		data.teams = slices.DeleteFunc(data.teams, func(team models.Team) bool {
+			return !round.HasTeam(team.ID)
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Teams of different cases are only compared on the global criteria
+	data.criteria = models.ApplicableCriteria(data.criteria, caseID)
+
+	data.scores, err = h.ScoreRepo.FindBy(ctx, roundID(round), bson.NilObjectID, bson.NilObjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Coverage is measured against the judges of the round or the assigned judges
+	if round != nil && len(round.Judges) > 0 {
+		data.judgeCount = len(round.Judges)
+	} else {
+		judges, err := h.UserRepo.FindByRole(ctx, models.Judge)
+		if err != nil {
+			return nil, err
+		}
+		data.judgeCount = len(judges)
+
+		assignments, err := h.AssignmentRepo.Find(ctx)
+		if err != nil {
+			return nil, err
+		}
+		cases, err := h.CaseRepo.Find(ctx)
+		if err != nil {
+			return nil, err
+		}
+		data.assigned = assignedJudges(data.teams, assignments, cases)
+	}
+
+	data.tieBreaks, err = scoring.ParseTieBreaks(data.settings.TieBreaks)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// publishedCheck hides the results from everyone but admins until they are published
+func publishedCheck(w http.ResponseWriter, r *http.Request, settings *models.Settings) bool {
+	userAuth := middleware.ExtractOptionalUserAuth(r)
+	if settings.ResultsPublished() || (userAuth != nil && userAuth.Role == models.Admin) {
+		return false
+	}
+
+	http.Error(w, "Access denied: the results are not published yet", http.StatusForbidden)
+	return true
+}
+
+// assignedJudges counts the judges assigned to every team directly or through its case
+func assignedJudges(teams []models.Team, assignments []models.Assignment, cases []models.Case) map[bson.ObjectID]int {
+	judges := make(map[bson.ObjectID]map[bson.ObjectID]bool)
+	add := func(team, judge bson.ObjectID) {
+		if judges[team] == nil {
+			judges[team] = make(map[bson.ObjectID]bool)
+		}
+		judges[team][judge] = true
+	}
+
+	for _, assignment := range assignments {
+		add(assignment.Team, assignment.Judge)
+	}
+	caseJudges := make(map[bson.ObjectID][]bson.ObjectID, len(cases))
+	for _, c := range cases {
+		caseJudges[c.ID] = c.Judges
+	}
+	for _, team := range teams {
+		for _, judge := range caseJudges[team.Case] {
+			add(team.ID, judge)
+		}
+	}
+
+	assigned := make(map[bson.ObjectID]int, len(judges))
+	for team, set := range judges {
+		assigned[team] = len(set)
+	}
+
+	return assigned
+}
+
+// method is the normalization method chosen in the settings
+func (d *leaderboardData) method() string {
+	if d.settings.NormalizationMethod == "" {
+		return scoring.MethodRaw
+	}
+
+	return d.settings.NormalizationMethod
+}
+
+// hideNames replaces the team names the viewer may not see yet.
+// Ranking uses the real names for tie breaks, so they are only replaced in the output.
+func (d *leaderboardData) hideNames(standings []scoring.Standing, viewer *models.User) []scoring.Standing {
+	teams := make(map[bson.ObjectID]*models.Team, len(d.teams))
+	for i := range d.teams {
+		teams[d.teams[i].ID] = &d.teams[i]
+	}
+
+	for i := range standings {
+		if team, ok := teams[standings[i].Team]; ok {
+			standings[i].Name = team.NameFor(viewer)
+		}
+	}
+
+	return standings
+}
+
+func (d *leaderboardData) rank(method string) ([]scoring.Standing, error) {
+	grades, err := scoring.Normalize(scoring.GradesFromScores(d.scores), d.criteria, method)
+	if err != nil {
+		return nil, err
+	}
+
+	return scoring.Rank(scoring.Input{
+		Teams:      d.teams,
+		Criteria:   d.criteria,
+		Grades:     grades,
+		JudgeCount: d.judgeCount,
+		Assigned:   d.assigned,
+		TieBreaks:  d.tieBreaks,
+	}), nil
+}
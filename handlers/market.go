@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/internal"
+	"github.com/SomeSuperCoder/global-chat/internal/matching"
+	"github.com/SomeSuperCoder/global-chat/internal/middleware"
+	"github.com/SomeSuperCoder/global-chat/internal/notify"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type MarketHandler struct {
+	ProfileRepo  *repository.ProfileRepo
+	PositionRepo *repository.PositionRepo
+	TeamRepo     *repository.TeamRepo
+	UserRepo     *repository.UserRepo
+	Notifier     *notify.Notifier
+}
+
+type MatchesResponse struct {
+	Positions []matching.Match `json:"positions"`
+	Profiles  []matching.Match `json:"profiles"`
+}
+
+func (h *MarketHandler) GetProfiles(w http.ResponseWriter, r *http.Request) {
+	board, ok := h.loadBoard(w, r)
+	if !ok {
+		return
+	}
+
+	RespondVisible(w, r, board.Profiles)
+}
+
+func (h *MarketHandler) GetPositions(w http.ResponseWriter, r *http.Request) {
+	board, ok := h.loadBoard(w, r)
+	if !ok {
+		return
+	}
+
+	RespondVisible(w, r, board.Positions)
+}
+
+func (h *MarketHandler) PutProfile(w http.ResponseWriter, r *http.Request) {
+	// Get auth data
+	userAuth := middleware.ExtractUserAuth(r)
+
+	// Check access
+	if !userAuth.Team.IsZero() && userAuth.Team != internal.UndefinedObjectID {
+		http.Error(w, "Access denied: you already are part of a team", http.StatusForbidden)
+		return
+	}
+
+	// Parse
+	var request struct {
+		Skills        []string      `json:"skills" validate:"required,min=1,max=20,dive,min=1,max=40"`
+		PreferredCase bson.ObjectID `json:"preferred_case"`
+		Role          string        `json:"role" validate:"max=40"`
+		About         string        `json:"about" validate:"max=1000"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	// Do work
+	err := h.ProfileRepo.Upsert(r.Context(), &models.SeekerProfile{
+		User:          userAuth.ID,
+		Skills:        matching.NormalizeSkills(request.Skills),
+		PreferredCase: request.PreferredCase,
+		Role:          strings.TrimSpace(request.Role),
+		About:         request.About,
+		UpdatedAt:     time.Now(),
+	})
+	if utils.CheckError(w, err, "Failed to update", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	fmt.Fprintf(w, "Successfully updated")
+}
+
+func (h *MarketHandler) DeleteProfile(w http.ResponseWriter, r *http.Request) {
+	err := h.ProfileRepo.DeleteByUser(r.Context(), middleware.ExtractUserAuth(r).ID)
+	if utils.CheckError(w, err, "Failed to delete", http.StatusInternalServerError) {
+		return
+	}
+
+	fmt.Fprintf(w, "Successfully deleted")
+}
+
+func (h *MarketHandler) CreatePosition(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	team, ok := h.loadOwnedTeam(w, r)
+	if !ok {
+		return
+	}
+
+	// Parse
+	var request struct {
+		Role        string   `json:"role" validate:"required,min=1,max=40"`
+		Skills      []string `json:"skills" validate:"required,min=1,max=20,dive,min=1,max=40"`
+		Description string   `json:"description" validate:"max=1000"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	// Do work
+	CreateInner(w, r, h.PositionRepo, &models.OpenPosition{
+		Team:        team.ID,
+		Role:        strings.TrimSpace(request.Role),
+		Skills:      matching.NormalizeSkills(request.Skills),
+		Description: request.Description,
+		CreatedAt:   time.Now(),
+	})
+}
+
+func (h *MarketHandler) DeletePosition(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	team, ok := h.loadOwnedTeam(w, r)
+	if !ok {
+		return
+	}
+
+	positionID, err := bson.ObjectIDFromHex(r.PathValue("positionId"))
+	if err != nil {
+		http.Error(w, "Invalid position ID provided", http.StatusBadRequest)
+		return
+	}
+
+	position, err := h.PositionRepo.GetByID(r.Context(), positionID)
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+	if position.Team != team.ID {
+		http.Error(w, "Not found: the position belongs to another team", http.StatusNotFound)
+		return
+	}
+
+	// Do work
+	err = h.PositionRepo.Delete(r.Context(), positionID)
+	if utils.CheckError(w, err, "Failed to delete", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	fmt.Fprintf(w, "Successfully deleted")
+}
+
+// GetMatches ranks positions for a solo participant and profiles for a team leader
+func (h *MarketHandler) GetMatches(w http.ResponseWriter, r *http.Request) {
+	board, ok := h.loadBoard(w, r)
+	if !ok {
+		return
+	}
+
+	userAuth := middleware.ExtractUserAuth(r)
+	response := MatchesResponse{
+		Positions: board.ForUser(userAuth.ID),
+		Profiles:  []matching.Match{},
+	}
+
+	team, err := h.TeamRepo.GetByID(r.Context(), userAuth.Team)
+	if err == nil && team.Leader == userAuth.ID {
+		response.Profiles = board.ForTeam(team.ID)
+	} else if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		utils.CheckError(w, err, "Failed to get team from DB", http.StatusInternalServerError)
+		return
+	}
+
+	RespondVisible(w, r, response)
+}
+
+// PushSuggestions sends every solo participant their best matches through the bot
+func (h *MarketHandler) PushSuggestions(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Load data
+	board, ok := h.loadBoard(w, r)
+	if !ok {
+		return
+	}
+
+	users, err := h.UserRepo.Find(r.Context())
+	if utils.CheckError(w, err, "Failed to get users", http.StatusInternalServerError) {
+		return
+	}
+	teams, err := h.TeamRepo.Find(r.Context())
+	if utils.CheckError(w, err, "Failed to get teams", http.StatusInternalServerError) {
+		return
+	}
+
+	// Do work
+	chats := make(map[bson.ObjectID]int64, len(users))
+	for _, user := range users {
+		chats[user.ID] = user.ChatID
+	}
+	teamNames := make(map[bson.ObjectID]string, len(teams))
+	for _, team := range teams {
+		teamNames[team.ID] = team.PublicName()
+	}
+
+	sent := 0
+	for _, profile := range board.Profiles {
+		matches := matching.Top(board.ForUser(profile.User), matching.SuggestedMatches)
+		if len(matches) == 0 {
+			continue
+		}
+
+		h.Notifier.Send(chats[profile.User], matching.FormatSuggestions(matches, teamNames))
+		sent++
+	}
+
+	// Respond
+	fmt.Fprintf(w, "Successfully sent %d suggestions", sent)
+}
+
+func (h *MarketHandler) loadBoard(w http.ResponseWriter, r *http.Request) (*matching.Board, bool) {
+	board, err := matching.LoadBoard(r.Context(), h.ProfileRepo, h.PositionRepo, h.TeamRepo, h.UserRepo)
+	if utils.CheckError(w, err, "Failed to load marketplace", http.StatusInternalServerError) {
+		return nil, false
+	}
+
+	return board, true
+}
+
+func (h *MarketHandler) loadOwnedTeam(w http.ResponseWriter, r *http.Request) (*models.Team, bool) {
+	var parsedId bson.ObjectID
+	var exit bool
+	if parsedId, exit = utils.ParseRequestID(w, r); exit {
+		return nil, false
+	}
+
+	team, err := h.TeamRepo.GetByID(r.Context(), parsedId)
+	if utils.CheckGetFromDB(w, err) {
+		return nil, false
+	}
+
+	userAuth := middleware.ExtractUserAuth(r)
+	if userAuth.Role != models.Admin && team.Leader != userAuth.ID {
+		http.Error(w, "Access denied: only the team leader can manage positions", http.StatusForbidden)
+		return nil, false
+	}
+
+	return team, true
+}
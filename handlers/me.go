@@ -4,9 +4,20 @@ import (
 	"net/http"
 
 	"github.com/SomeSuperCoder/global-chat/internal/middleware"
+	"github.com/SomeSuperCoder/global-chat/repository"
 	"github.com/SomeSuperCoder/global-chat/utils"
 )
 
-func MeHandler(w http.ResponseWriter, r *http.Request) {
-	utils.RespondWithJSON(w, middleware.ExtractUserAuth(r))
+type MeHandler struct {
+	Repo *repository.UserRepo
+}
+
+func (h *MeHandler) Get(w http.ResponseWriter, r *http.Request) {
+	// Session claims only carry the identity, so load the full profile
+	user, err := h.Repo.GetByID(r.Context(), middleware.ExtractUserAuth(r).ID)
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+
+	RespondVisible(w, r, user)
 }
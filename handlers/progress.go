@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/SomeSuperCoder/global-chat/internal/notify"
+	"github.com/SomeSuperCoder/global-chat/internal/progress"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type ProgressHandler struct {
+	UserRepo       *repository.UserRepo
+	TeamRepo       *repository.TeamRepo
+	CriterionRepo  *repository.CriterionRepo
+	AssignmentRepo *repository.AssignmentRepo
+	CaseRepo       *repository.CaseRepo
+	ScoreRepo      *repository.ScoreRepo
+	RoundRepo      *repository.RoundRepo
+	Notifier       *notify.Notifier
+}
+
+type ProgressResponse struct {
+	Round    bson.ObjectID            `json:"round"`
+	Expected int                      `json:"expected"`
+	Scored   int                      `json:"scored"`
+	Judges   []progress.JudgeProgress `json:"judges"`
+}
+
+// Get shows how many of their teams and criteria every judge has scored
+func (h *ProgressHandler) Get(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Load data
+	round, judges, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	// Respond
+	response := ProgressResponse{
+		Round:  roundID(round),
+		Judges: judges,
+	}
+	for _, judge := range judges {
+		response.Expected += judge.Expected
+		response.Scored += judge.Scored
+	}
+	utils.RespondWithJSON(w, response)
+}
+
+// Nudge reminds the judge of the teams they haven't scored yet
+func (h *ProgressHandler) Nudge(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Parse
+	var judgeID bson.ObjectID
+	var exit bool
+	if judgeID, exit = utils.ParseRequestID(w, r); exit {
+		return
+	}
+
+	// Load data
+	_, judges, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	var judge *progress.JudgeProgress
+	for i := range judges {
+		if judges[i].Judge == judgeID {
+			judge = &judges[i]
+		}
+	}
+	if judge == nil {
+		http.Error(w, "The user doesn't judge any team", http.StatusNotFound)
+		return
+	}
+	if judge.Done() {
+		http.Error(w, "The judge has scored every team", http.StatusConflict)
+		return
+	}
+	if judge.ChatID == 0 {
+		http.Error(w, "The judge hasn't started the bot", http.StatusConflict)
+		return
+	}
+
+	// Do work
+	h.Notifier.Send(judge.ChatID, progress.Reminder(judge))
+
+	// Respond
+	fmt.Fprintf(w, "Reminder sent")
+}
+
+func (h *ProgressHandler) load(w http.ResponseWriter, r *http.Request) (*models.Round, []progress.JudgeProgress, bool) {
+	id, ok := parseOptionalID(w, r, "round")
+	if !ok {
+		return nil, nil, false
+	}
+
+	var round *models.Round
+	if !id.IsZero() {
+		var err error
+		round, err = h.RoundRepo.GetByID(r.Context(), id)
+		if utils.CheckGetFromDB(w, err) {
+			return nil, nil, false
+		}
+	}
+
+	judges, err := progress.Load(r.Context(), h.UserRepo, h.TeamRepo, h.CriterionRepo, h.AssignmentRepo, h.CaseRepo, h.ScoreRepo, round)
+	if utils.CheckError(w, err, "Failed to load judging progress", http.StatusInternalServerError) {
+		return nil, nil, false
+	}
+
+	return round, judges, true
+}
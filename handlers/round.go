@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/internal/notify"
+	"github.com/SomeSuperCoder/global-chat/internal/scoring"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type RoundHandler struct {
+	Repo          *repository.RoundRepo
+	CriterionRepo *repository.CriterionRepo
+	TeamRepo      *repository.TeamRepo
+	UserRepo      *repository.UserRepo
+	Leaderboard   *LeaderboardHandler
+	Notifier      *notify.Notifier
+}
+
+type AdvanceResponse struct {
+	Round bson.ObjectID   `json:"round"`
+	Teams []bson.ObjectID `json:"teams"`
+}
+
+func (h *RoundHandler) Get(w http.ResponseWriter, r *http.Request) {
+	Get(w, r, h.Repo)
+}
+
+func (h *RoundHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	GetByID(w, r, h.Repo)
+}
+
+func (h *RoundHandler) Create(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Parse
+	var request struct {
+		Name     string          `json:"name" validate:"required,min=1,max=40"`
+		Order    int             `json:"order"`
+		Criteria []bson.ObjectID `json:"criteria" validate:"required,min=1"`
+		Judges   []bson.ObjectID `json:"judges"`
+		Teams    []bson.ObjectID `json:"teams"`
+		StartsAt time.Time       `json:"starts_at"`
+		EndsAt   time.Time       `json:"ends_at"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	round := &models.Round{
+		Name:     request.Name,
+		Order:    request.Order,
+		Criteria: request.Criteria,
+		Judges:   orEmpty(request.Judges),
+		Teams:    orEmpty(request.Teams),
+		StartsAt: request.StartsAt,
+		EndsAt:   request.EndsAt,
+	}
+	if h.checkRound(w, r, round) {
+		return
+	}
+
+	CreateInner(w, r, h.Repo, round)
+}
+
+func (h *RoundHandler) Update(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Load data
+	var parsedId bson.ObjectID
+	var exit bool
+	if parsedId, exit = utils.ParseRequestID(w, r); exit {
+		return
+	}
+
+	round, err := h.Repo.GetByID(r.Context(), parsedId)
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+
+	// Parse
+	var request struct {
+		Name     string           `json:"name" bson:"name,omitempty" validate:"omitempty,min=1,max=40"`
+		Order    *int             `json:"order" bson:"order,omitempty"`
+		Criteria *[]bson.ObjectID `json:"criteria" bson:"criteria,omitempty" validate:"omitempty,min=1"`
+		Judges   *[]bson.ObjectID `json:"judges" bson:"judges,omitempty"`
+		Teams    *[]bson.ObjectID `json:"teams" bson:"teams,omitempty"`
+		StartsAt *time.Time       `json:"starts_at" bson:"starts_at,omitempty"`
+		EndsAt   *time.Time       `json:"ends_at" bson:"ends_at,omitempty"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	// Validate the resulting round
+	if request.Criteria != nil {
+		round.Criteria = *request.Criteria
+	}
+	if request.Judges != nil {
+		round.Judges = *request.Judges
+	}
+	if request.Teams != nil {
+		round.Teams = *request.Teams
+	}
+	if request.StartsAt != nil {
+		round.StartsAt = *request.StartsAt
+	}
+	if request.EndsAt != nil {
+		round.EndsAt = *request.EndsAt
+	}
+	if h.checkRound(w, r, round) {
+		return
+	}
+
+	UpdateInner(w, r, h.Repo, parsedId, request)
+}
+
+func (h *RoundHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	AdminOnlyDelete(w, r, h.Repo)
+}
+
+// Advance lets the best teams of the round, or the teams picked by an admin, take part in the next round
+func (h *RoundHandler) Advance(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Load data
+	var parsedId bson.ObjectID
+	var exit bool
+	if parsedId, exit = utils.ParseRequestID(w, r); exit {
+		return
+	}
+
+	round, err := h.Repo.GetByID(r.Context(), parsedId)
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+
+	// Parse
+	var request struct {
+		To    bson.ObjectID   `json:"to"`
+		Top   int             `json:"top" validate:"required_without=Teams,excluded_with=Teams,omitempty,min=1"`
+		Teams []bson.ObjectID `json:"teams" validate:"required_without=Top,omitempty,min=1"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	var next *models.Round
+	if request.To.IsZero() {
+		next, err = h.Repo.GetNext(r.Context(), round)
+	} else {
+		next, err = h.Repo.GetByID(r.Context(), request.To)
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		http.Error(w, "There is no round to advance to", http.StatusNotFound)
+		return
+	} else if utils.CheckError(w, err, "Failed to get round from DB", http.StatusInternalServerError) {
+		return
+	}
+	if next.ID == round.ID {
+		http.Error(w, "A round can't advance to itself", http.StatusBadRequest)
+		return
+	}
+
+	// Do work
+	teams := request.Teams
+	if request.Top > 0 {
+		teams, err = h.topTeams(r.Context(), round, request.Top)
+		if utils.CheckError(w, err, "Failed to rank teams", http.StatusInternalServerError) {
+			return
+		}
+		if len(teams) == 0 {
+			http.Error(w, "No team has been scored in this round", http.StatusConflict)
+			return
+		}
+	} else if h.checkTeams(w, r, teams) {
+		return
+	}
+
+	err = h.Repo.Update(r.Context(), next.ID, bson.M{
+		"teams": teams,
+	})
+	if utils.CheckError(w, err, "Failed to update", http.StatusInternalServerError) {
+		return
+	}
+
+	for _, team := range teams {
+		members, err := h.TeamRepo.GetMembers(r.Context(), team)
+		if err == nil {
+			h.Notifier.SendToUsers(members, fmt.Sprintf("Ваша команда прошла в раунд «%s»", next.Name))
+		}
+	}
+
+	// Respond
+	utils.RespondWithJSON(w, AdvanceResponse{
+		Round: next.ID,
+		Teams: teams,
+	})
+}
+
+// topTeams returns the first top teams of the ranking, teams tied at the cut all advance.
+// Teams nobody has graded never advance and don't take places at the cut.
+func (h *RoundHandler) topTeams(ctx context.Context, round *models.Round, top int) ([]bson.ObjectID, error) {
+	data, err := h.Leaderboard.loadData(ctx, bson.NilObjectID, round)
+	if err != nil {
+		return nil, err
+	}
+
+	standings, err := data.rank(data.method())
+	if err != nil {
+		return nil, err
+	}
+
+	scored := []scoring.Standing{}
+	for _, standing := range standings {
+		if standing.Judges > 0 {
+			scored = append(scored, standing)
+		}
+	}
+
+	teams := []bson.ObjectID{}
+	for i, standing := range scored {
+		// Past the cut only the teams tied with the last place advance
+		if i >= top && standing.Rank != scored[top-1].Rank {
+			break
+		}
+		teams = append(teams, standing.Team)
+	}
+
+	return teams, nil
+}
+
+// checkRound makes sure the round refers to existing criteria, judges and teams
+func (h *RoundHandler) checkRound(w http.ResponseWriter, r *http.Request, round *models.Round) bool {
+	if !round.StartsAt.IsZero() && !round.EndsAt.IsZero() && !round.EndsAt.After(round.StartsAt) {
+		http.Error(w, "JSON validation failed: ends_at must be after starts_at", http.StatusBadRequest)
+		return true
+	}
+
+	criteria, err := h.CriterionRepo.FindByIDs(r.Context(), round.Criteria)
+	if utils.CheckError(w, err, "Failed to get criteria", http.StatusInternalServerError) {
+		return true
+	}
+	if len(criteria) != len(unique(round.Criteria)) {
+		http.Error(w, "Some of the criteria don't exist", http.StatusBadRequest)
+		return true
+	}
+
+	if checkJudges(w, r, h.UserRepo, round.Judges) {
+		return true
+	}
+
+	return h.checkTeams(w, r, round.Teams)
+}
+
+// checkJudges makes sure every user exists and is a judge
+func checkJudges(w http.ResponseWriter, r *http.Request, userRepo *repository.UserRepo, ids []bson.ObjectID) bool {
+	judges, err := userRepo.FindByIDs(r.Context(), ids)
+	if utils.CheckError(w, err, "Failed to get judges", http.StatusInternalServerError) {
+		return true
+	}
+	for _, judge := range judges {
+		if judge.Role != models.Judge {
+			http.Error(w, fmt.Sprintf("User %s is not a judge", judge.ID.Hex()), http.StatusBadRequest)
+			return true
+		}
+	}
+	if len(judges) != len(unique(ids)) {
+		http.Error(w, "Some of the judges don't exist", http.StatusBadRequest)
+		return true
+	}
+
+	return false
+}
+
+func (h *RoundHandler) checkTeams(w http.ResponseWriter, r *http.Request, ids []bson.ObjectID) bool {
+	teams, err := h.TeamRepo.FindByIDs(r.Context(), ids)
+	if utils.CheckError(w, err, "Failed to get teams", http.StatusInternalServerError) {
+		return true
+	}
+	if len(teams) != len(unique(ids)) {
+		http.Error(w, "Some of the teams don't exist", http.StatusBadRequest)
+		return true
+	}
+
+	return false
+}
+
+func unique(ids []bson.ObjectID) map[bson.ObjectID]bool {
+	set := make(map[bson.ObjectID]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+
+	return set
+}
+
+func orEmpty(ids []bson.ObjectID) []bson.ObjectID {
+	if ids == nil {
+		return []bson.ObjectID{}
+	}
+
+	return ids
+}
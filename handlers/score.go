@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/internal/assignment"
+	"github.com/SomeSuperCoder/global-chat/internal/middleware"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type ScoreHandler struct {
+	Repo           *repository.ScoreRepo
+	TeamRepo       *repository.TeamRepo
+	CriterionRepo  *repository.CriterionRepo
+	AssignmentRepo *repository.AssignmentRepo
+	RoundRepo      *repository.RoundRepo
+	CaseRepo       *repository.CaseRepo
+	SettingsRepo   *repository.SettingsRepo
+	ConflictRepo   *repository.ConflictRepo
+}
+
+// ScoreSheet lists the criteria the team is scored on along with the scores the caller may see
+type ScoreSheet struct {
+	Team     bson.ObjectID      `json:"team"`
+	Case     bson.ObjectID      `json:"case"`
+	Round    bson.ObjectID      `json:"round"`
+	Criteria []models.Criterion `json:"criteria"`
+	Scores   []models.Score     `json:"scores"`
+}
+
+// GetByTeam lists every score of the team to admins, their own scores to judges and,
+// once the results are published, the scores of their team to participants
+func (h *ScoreHandler) GetByTeam(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+	round, ok := h.loadRound(w, r)
+	if !ok {
+		return
+	}
+
+	// Check access
+	judge, visible, ok := h.scoreAccess(w, r, team)
+	if !ok {
+		return
+	}
+	if !visible {
+		http.Error(w, "Access denied: the results are not published yet", http.StatusForbidden)
+		return
+	}
+
+	// Do work
+	scores, err := h.Repo.FindBy(r.Context(), roundID(round), judge, team.ID)
+	if utils.CheckError(w, err, "Failed to get scores", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	RespondVisible(w, r, scores)
+}
+
+// GetSheet returns the criteria that apply to the case and round of the team, with the scores the caller may see
+func (h *ScoreHandler) GetSheet(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+	round, ok := h.loadRound(w, r)
+	if !ok {
+		return
+	}
+
+	// Check access
+	judge, visible, ok := h.scoreAccess(w, r, team)
+	if !ok {
+		return
+	}
+
+	// Do work
+	var criteria []models.Criterion
+	var err error
+	if round == nil {
+		criteria, err = h.CriterionRepo.Find(r.Context())
+	} else {
+		criteria, err = h.CriterionRepo.FindByIDs(r.Context(), round.Criteria)
+	}
+	if utils.CheckError(w, err, "Failed to get criteria", http.StatusInternalServerError) {
+		return
+	}
+
+	sheet := ScoreSheet{
+		Team:     team.ID,
+		Case:     team.Case,
+		Round:    roundID(round),
+		Criteria: models.ApplicableCriteria(criteria, team.Case),
+		Scores:   []models.Score{},
+	}
+	if visible {
+		scores, err := h.Repo.FindBy(r.Context(), roundID(round), judge, team.ID)
+		if utils.CheckError(w, err, "Failed to get scores", http.StatusInternalServerError) {
+			return
+		}
+		for _, score := range scores {
+			if slices.ContainsFunc(sheet.Criteria, func(criterion models.Criterion) bool { return criterion.ID == score.Criterion }) {
+				sheet.Scores = append(sheet.Scores, score)
+			}
+		}
+	}
+
+	// Respond
+	RespondVisible(w, r, sheet)
+}
+
+// scoreAccess returns whose scores of the team the caller may see, a zero judge means every judge.
+// Members of the team see the scores once the results are published, other participants are denied.
+func (h *ScoreHandler) scoreAccess(w http.ResponseWriter, r *http.Request, team *models.Team) (bson.ObjectID, bool, bool) {
+	userAuth := middleware.ExtractUserAuth(r)
+	switch userAuth.Role {
+	case models.Admin:
+		return bson.NilObjectID, true, true
+	case models.Judge:
+		return userAuth.ID, true, true
+	}
+
+	if userAuth.Team != team.ID {
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return bson.NilObjectID, false, false
+	}
+	settings, err := h.SettingsRepo.Get(r.Context())
+	if utils.CheckGetFromDB(w, err) {
+		return bson.NilObjectID, false, false
+	}
+
+	return bson.NilObjectID, settings.ResultsPublished(), true
+}
+
+// Put sets the score of the judge for the team on the criterion
+func (h *ScoreHandler) Put(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+	criterion, ok := h.loadCriterion(w, r)
+	if !ok {
+		return
+	}
+	round, ok := h.loadRound(w, r)
+	if !ok {
+		return
+	}
+
+	// Check access
+	userAuth := middleware.ExtractUserAuth(r)
+	if h.judgeCheck(w, r, userAuth, team, round) {
+		return
+	}
+	if round != nil && !round.HasCriterion(criterion.ID) {
+		http.Error(w, "The criterion is not used in this round", http.StatusBadRequest)
+		return
+	}
+	if !criterion.AppliesTo(team.Case) {
+		http.Error(w, "The criterion doesn't apply to the case of the team", http.StatusBadRequest)
+		return
+	}
+
+	// Parse
+	var request struct {
+		Value   *int   `json:"value" validate:"required"`
+		Comment string `json:"comment" validate:"max=2000"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	// Validate
+	minScore, maxScore := criterion.ScoreRange()
+	if *request.Value < minScore || *request.Value > maxScore {
+		http.Error(w, fmt.Sprintf("JSON validation failed: value must be between %d and %d", minScore, maxScore), http.StatusBadRequest)
+		return
+	}
+
+	// Do work
+	score, err := h.Repo.Upsert(r.Context(), &models.Score{
+		Judge:     userAuth.ID,
+		Team:      team.ID,
+		Criterion: criterion.ID,
+		Round:     roundID(round),
+		Value:     *request.Value,
+		Comment:   request.Comment,
+	})
+	if utils.CheckError(w, err, "Failed to save score", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	RespondVisible(w, r, score)
+}
+
+func (h *ScoreHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+	criterionID, err := bson.ObjectIDFromHex(r.PathValue("criterionId"))
+	if utils.CheckError(w, err, "Invalid criterion ID", http.StatusBadRequest) {
+		return
+	}
+	round, ok := h.loadRound(w, r)
+	if !ok {
+		return
+	}
+
+	// Check access
+	userAuth := middleware.ExtractUserAuth(r)
+	if h.judgeCheck(w, r, userAuth, team, round) {
+		return
+	}
+
+	// Do work
+	deleted, err := h.Repo.DeleteOne(r.Context(), roundID(round), userAuth.ID, team.ID, criterionID)
+	if utils.CheckError(w, err, "Failed to delete", http.StatusInternalServerError) {
+		return
+	}
+	if !deleted {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	// Respond
+	fmt.Fprintf(w, "Successfully deleted")
+}
+
+// judgeCheck only lets the judges of the round, the judges assigned to the team or the judges of its case score it.
+// Conflicts are checked again since teams change after the assignments are made.
+func (h *ScoreHandler) judgeCheck(w http.ResponseWriter, r *http.Request, userAuth *models.User, team *models.Team, round *models.Round) bool {
+	if userAuth.Role != models.Judge {
+		http.Error(w, "Access denied: only judges can score teams", http.StatusForbidden)
+		return true
+	}
+
+	settings, err := h.SettingsRepo.Get(r.Context())
+	if utils.CheckGetFromDB(w, err) {
+		return true
+	}
+	if settings.JudgingState != models.JudgingOpen {
+		http.Error(w, fmt.Sprintf("Access denied: judging is %s", settings.JudgingState), http.StatusForbidden)
+		return true
+	}
+
+	conflicted, err := h.isConflicted(r, userAuth, team)
+	if utils.CheckError(w, err, "Failed to check conflicts", http.StatusInternalServerError) {
+		return true
+	}
+	if conflicted {
+		http.Error(w, "Access denied: you have a conflict of interest with this team", http.StatusForbidden)
+		return true
+	}
+
+	if round != nil {
+		if !round.IsOpen(time.Now()) {
+			http.Error(w, "Access denied: the round is not open for scoring", http.StatusForbidden)
+			return true
+		}
+		if !round.HasTeam(team.ID) {
+			http.Error(w, "Access denied: the team doesn't take part in this round", http.StatusForbidden)
+			return true
+		}
+		if len(round.Judges) > 0 {
+			if !slices.Contains(round.Judges, userAuth.ID) {
+				http.Error(w, "Access denied: you are not a judge of this round", http.StatusForbidden)
+				return true
+			}
+			return false
+		}
+	}
+
+	assigned, err := h.AssignmentRepo.IsAssigned(r.Context(), userAuth.ID, team.ID)
+	if utils.CheckError(w, err, "Failed to check assignments", http.StatusInternalServerError) {
+		return true
+	}
+	if !assigned && !team.Case.IsZero() {
+		teamCase, err := h.CaseRepo.GetByID(r.Context(), team.Case)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			utils.CheckError(w, err, "Failed to get case from DB", http.StatusInternalServerError)
+			return true
+		}
+		assigned = err == nil && teamCase.HasJudge(userAuth.ID)
+	}
+	if !assigned {
+		http.Error(w, "Access denied: you are not assigned to this team", http.StatusForbidden)
+		return true
+	}
+
+	return false
+}
+
+// isConflicted resolves the conflicts of the judge against the current members of the team
+func (h *ScoreHandler) isConflicted(r *http.Request, judge *models.User, team *models.Team) (bool, error) {
+	conflicts, err := h.ConflictRepo.FindByJudge(r.Context(), judge.ID)
+	if err != nil {
+		return false, err
+	}
+
+	members, err := h.TeamRepo.GetMembers(r.Context(), team.ID)
+	if err != nil {
+		return false, err
+	}
+
+	return assignment.Conflicts(conflicts, append(members, *judge)).Has(judge.ID, team.ID), nil
+}
+
+func (h *ScoreHandler) loadTeam(w http.ResponseWriter, r *http.Request) (*models.Team, bool) {
+	var parsedId bson.ObjectID
+	var exit bool
+	if parsedId, exit = utils.ParseRequestID(w, r); exit {
+		return nil, false
+	}
+
+	team, err := h.TeamRepo.GetByID(r.Context(), parsedId)
+	if utils.CheckGetFromDB(w, err) {
+		return nil, false
+	}
+
+	return team, true
+}
+
+// loadRound reads the optional round query parameter, nil means scores given outside of rounds
+func (h *ScoreHandler) loadRound(w http.ResponseWriter, r *http.Request) (*models.Round, bool) {
+	id, ok := parseOptionalID(w, r, "round")
+	if !ok || id.IsZero() {
+		return nil, ok
+	}
+
+	round, err := h.RoundRepo.GetByID(r.Context(), id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		http.Error(w, "Round not found", http.StatusNotFound)
+		return nil, false
+	} else if utils.CheckError(w, err, "Failed to get round from DB", http.StatusInternalServerError) {
+		return nil, false
+	}
+
+	return round, true
+}
+
+func roundID(round *models.Round) bson.ObjectID {
+	if round == nil {
+		return bson.NilObjectID
+	}
+
+	return round.ID
+}
+
+func (h *ScoreHandler) loadCriterion(w http.ResponseWriter, r *http.Request) (*models.Criterion, bool) {
+	criterionID, err := bson.ObjectIDFromHex(r.PathValue("criterionId"))
+	if utils.CheckError(w, err, "Invalid criterion ID", http.StatusBadRequest) {
+		return nil, false
+	}
+
+	criterion, err := h.CriterionRepo.GetByID(r.Context(), criterionID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		http.Error(w, "Criterion not found", http.StatusNotFound)
+		return nil, false
+	} else if utils.CheckError(w, err, "Failed to get criterion from DB", http.StatusInternalServerError) {
+		return nil, false
+	}
+
+	return criterion, true
+}
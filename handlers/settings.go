@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/internal/scoring"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+)
+
+type SettingsHandler struct {
+	Repo *repository.SettingsRepo
+}
+
+func (h *SettingsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.Repo.Get(r.Context())
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+
+	RespondVisible(w, r, settings)
+}
+
+func (h *SettingsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	// Parse
+	var request struct {
+		TeamRules              *models.TeamRules `json:"team_rules" bson:"team_rules,omitempty" validate:"omitempty,admin"`
+		BannedWords            *[]string         `json:"banned_words" bson:"banned_words,omitempty" validate:"omitempty,admin,dive,min=1,max=100"`
+		TeamNameApproval       *bool             `json:"team_name_approval" bson:"team_name_approval,omitempty" validate:"omitempty,admin"`
+		NormalizationMethod    string            `json:"normalization_method" bson:"normalization_method,omitempty" validate:"omitempty,admin"`
+		TieBreaks              *[]string         `json:"tie_breaks" bson:"tie_breaks,omitempty" validate:"omitempty,admin"`
+		VotingStart            *time.Time        `json:"voting_start" bson:"voting_start,omitempty" validate:"omitnil,admin"`
+		VotingEnd              *time.Time        `json:"voting_end" bson:"voting_end,omitempty" validate:"omitnil,admin"`
+		VotesPerUser           *int              `json:"votes_per_user" bson:"votes_per_user,omitempty" validate:"omitempty,admin,min=1,max=100"`
+		HackathonStart         time.Time         `json:"hackathon_start" bson:"hackathon_start,omitempty" validate:"omitempty,admin"`
+		CaseSelectionDeadline  time.Time         `json:"case_selection_deadline" bson:"case_selection_deadline,omitempty" validate:"omitempty,admin"`
+		SubmissionDeadline     time.Time         `json:"submission_deadline" bson:"submission_deadline,omitempty" validate:"omitempty,admin"`
+		LateSubmissionGraceMin *int              `json:"late_submission_grace_min" bson:"late_submission_grace_min,omitempty" validate:"omitempty,admin,min=0"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	// Validate
+	if rules := request.TeamRules; rules != nil && rules.MaxMembers > 0 && rules.MinMembers > rules.MaxMembers {
+		http.Error(w, "JSON validation failed: min_members is greater than max_members", http.StatusBadRequest)
+		return
+	}
+	if request.NormalizationMethod != "" && !scoring.ValidMethod(request.NormalizationMethod) {
+		http.Error(w, "JSON validation failed: unknown normalization method "+request.NormalizationMethod, http.StatusBadRequest)
+		return
+	}
+	if request.NormalizationMethod != "" {
+		settings, err := h.Repo.Get(r.Context())
+		if utils.CheckGetFromDB(w, err) {
+			return
+		}
+		current := settings.NormalizationMethod
+		if current == "" {
+			current = scoring.MethodRaw
+		}
+		if settings.ResultsPublished() && request.NormalizationMethod != current {
+			http.Error(w, "The normalization method can't be changed while the results are published", http.StatusConflict)
+			return
+		}
+	}
+	if request.VotingStart != nil || request.VotingEnd != nil {
+		settings, err := h.Repo.Get(r.Context())
+		if utils.CheckGetFromDB(w, err) {
+			return
+		}
+		start, end := settings.VotingStart, settings.VotingEnd
+		if request.VotingStart != nil {
+			start = *request.VotingStart
+		}
+		if request.VotingEnd != nil {
+			end = *request.VotingEnd
+		}
+		if !start.IsZero() && !end.IsZero() && !end.After(start) {
+			http.Error(w, "JSON validation failed: voting_end must be after voting_start", http.StatusBadRequest)
+			return
+		}
+	}
+	if request.TieBreaks != nil {
+		if _, err := scoring.ParseTieBreaks(*request.TieBreaks); utils.CheckJSONValidError(w, err) {
+			return
+		}
+	}
+
+	// Do work
+	// Zero times are left out of the update, so they are removed explicitly
+	var unset []string
+	if request.VotingStart != nil && request.VotingStart.IsZero() {
+		unset = append(unset, "voting_start")
+	}
+	if request.VotingEnd != nil && request.VotingEnd.IsZero() {
+		unset = append(unset, "voting_end")
+	}
+
+	err := h.Repo.Update(r.Context(), request, unset...)
+	if utils.CheckError(w, err, "Failed to update", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	fmt.Fprintf(w, "Successfully updated")
+}
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/internal/middleware"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type SubmissionHandler struct {
+	Repo         *repository.SubmissionRepo
+	TeamRepo     *repository.TeamRepo
+	SettingsRepo *repository.SettingsRepo
+}
+
+func (h *SubmissionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+
+	// Get auth data
+	userAuth := middleware.ExtractUserAuth(r)
+
+	// Check access
+	if userAuth.Role != models.Admin && team.Leader != userAuth.ID {
+		http.Error(w, "Access denied: only the team leader can submit", http.StatusForbidden)
+		return
+	}
+
+	settings, err := h.SettingsRepo.Get(r.Context())
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+
+	now := time.Now()
+	lock := settings.SubmissionLock()
+	if !lock.IsZero() && now.After(lock) && userAuth.Role != models.Admin {
+		http.Error(w, "Access denied: submissions are locked", http.StatusForbidden)
+		return
+	}
+
+	// Parse
+	var request struct {
+		Repos           []string `json:"repos" validate:"required,min=1,dive,url"`
+		PresentationURI string   `json:"presentation_uri" validate:"omitempty,url"`
+		DemoVideoURI    string   `json:"demo_video_uri" validate:"omitempty,url"`
+		Description     string   `json:"description" validate:"required,max=5000"`
+		// Lets an admin replace the final version, also after the lock
+		Final bool `json:"final"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+	if request.Final && userAuth.Role != models.Admin {
+		http.Error(w, "Access denied: only the admin can mark the final version", http.StatusForbidden)
+		return
+	}
+
+	// Do work
+	submission := &models.Submission{
+		Team:            team.ID,
+		Repos:           request.Repos,
+		PresentationURI: request.PresentationURI,
+		DemoVideoURI:    request.DemoVideoURI,
+		Description:     request.Description,
+		Case:            team.Case,
+		SubmittedBy:     userAuth.ID,
+		CreatedAt:       now,
+		Late:            !settings.SubmissionDeadline.IsZero() && now.After(settings.SubmissionDeadline),
+	}
+	createdID, err := h.Repo.CreateVersion(r.Context(), submission)
+	if utils.CheckError(w, err, "Failed to create", http.StatusInternalServerError) {
+		return
+	}
+	if request.Final {
+		err = h.Repo.MarkFinal(r.Context(), team.ID, createdID)
+		if utils.CheckError(w, err, "Failed to mark the final version", http.StatusInternalServerError) {
+			return
+		}
+	}
+
+	// Respond
+	fmt.Fprintln(w, createdID.Hex())
+}
+
+// GetByTeam lists every version to the team and admins. Judges only get the final one.
+func (h *SubmissionHandler) GetByTeam(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+
+	// Check access
+	userAuth := middleware.ExtractUserAuth(r)
+	if userAuth.Role == models.Judge {
+		h.respondWithFinal(w, r, team)
+		return
+	}
+	if userAuth.Role != models.Admin && userAuth.Team != team.ID {
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return
+	}
+
+	// Do work
+	submissions, err := h.Repo.FindByTeam(r.Context(), team.ID)
+	if utils.CheckError(w, err, "Failed to get submissions", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	RespondVisible(w, r, submissions)
+}
+
+func (h *SubmissionHandler) GetFinal(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+
+	// Check access
+	userAuth := middleware.ExtractUserAuth(r)
+	if userAuth.Role == models.Participant && userAuth.Team != team.ID {
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return
+	}
+
+	h.respondWithFinal(w, r, team)
+}
+
+// MarkFinal lets an admin pick the version the judges evaluate
+func (h *SubmissionHandler) MarkFinal(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Load data
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+
+	submissionID, err := bson.ObjectIDFromHex(r.PathValue("submissionId"))
+	if err != nil {
+		http.Error(w, "Invalid submission ID provided", http.StatusBadRequest)
+		return
+	}
+
+	// Do work
+	err = h.Repo.MarkFinal(r.Context(), team.ID, submissionID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		http.Error(w, "Submission not found", http.StatusNotFound)
+		return
+	} else if mongo.IsDuplicateKeyError(err) {
+		http.Error(w, "The final version was changed by another request, try again", http.StatusConflict)
+		return
+	} else if utils.CheckError(w, err, "Failed to mark the final version", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	fmt.Fprintf(w, "Successfully updated")
+}
+
+// respondWithFinal responds with the version marked as final.
+// Until one is marked, the last version submitted before the lock is final,
+// and once the lock passes it gets marked so it no longer depends on the settings.
+func (h *SubmissionHandler) respondWithFinal(w http.ResponseWriter, r *http.Request, team *models.Team) {
+	submission, err := h.Repo.GetFinal(r.Context(), team.ID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		submission, err = h.lockedVersion(r, team)
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		http.Error(w, "The team has not submitted anything", http.StatusNotFound)
+		return
+	} else if utils.CheckError(w, err, "Failed to get submission", http.StatusInternalServerError) {
+		return
+	}
+
+	RespondVisible(w, r, submission)
+}
+
+func (h *SubmissionHandler) lockedVersion(r *http.Request, team *models.Team) (*models.Submission, error) {
+	settings, err := h.SettingsRepo.Get(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	lock := settings.SubmissionLock()
+	submission, err := h.Repo.GetLatest(r.Context(), team.ID, lock)
+	if err != nil {
+		return nil, err
+	}
+
+	if !lock.IsZero() && time.Now().After(lock) {
+		err = h.Repo.MarkFinal(r.Context(), team.ID, submission.ID)
+		// A concurrent request marked a version first
+		if mongo.IsDuplicateKeyError(err) {
+			return h.Repo.GetFinal(r.Context(), team.ID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		submission.Final = true
+	}
+
+	return submission, nil
+}
+
+func (h *SubmissionHandler) loadTeam(w http.ResponseWriter, r *http.Request) (*models.Team, bool) {
+	var parsedId bson.ObjectID
+	var exit bool
+	if parsedId, exit = utils.ParseRequestID(w, r); exit {
+		return nil, false
+	}
+
+	team, err := h.TeamRepo.GetByID(r.Context(), parsedId)
+	if utils.CheckGetFromDB(w, err) {
+		return nil, false
+	}
+
+	return team, true
+}
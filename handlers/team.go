@@ -4,10 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/SomeSuperCoder/global-chat/internal"
 	"github.com/SomeSuperCoder/global-chat/internal/middleware"
+	"github.com/SomeSuperCoder/global-chat/internal/notify"
+	"github.com/SomeSuperCoder/global-chat/internal/teamrules"
 	"github.com/SomeSuperCoder/global-chat/internal/validators"
+	"github.com/SomeSuperCoder/global-chat/internal/webhook"
 	"github.com/SomeSuperCoder/global-chat/models"
 	"github.com/SomeSuperCoder/global-chat/repository"
 	"github.com/SomeSuperCoder/global-chat/utils"
@@ -16,8 +20,13 @@ import (
 )
 
 type TeamHandler struct {
-	TeamRepo *repository.TeamRepo
-	UserRepo *repository.UserRepo
+	TeamRepo     *repository.TeamRepo
+	CaseRepo     *repository.CaseRepo
+	UserRepo     *repository.UserRepo
+	SessionRepo  *repository.SessionRepo
+	SettingsRepo *repository.SettingsRepo
+	CounterRepo  *repository.CounterRepo
+	Notifier     *notify.Notifier
 }
 
 type TeamsResponse struct {
@@ -26,7 +35,13 @@ type TeamsResponse struct {
 }
 
 func (h *TeamHandler) GetPaged(w http.ResponseWriter, r *http.Request) {
-	FindPaged(w, r, h.TeamRepo, func(values []models.Team, totalCount int64) any {
+	// Teams with unapproved names are only listed to admins
+	var repo PagedFinder[models.Team] = PagedFinderFunc[models.Team](h.TeamRepo.FindPagedPublic)
+	if userAuth := middleware.ExtractOptionalUserAuth(r); userAuth != nil && userAuth.Role == models.Admin {
+		repo = h.TeamRepo
+	}
+
+	FindPaged(w, r, repo, func(values []models.Team, totalCount int64) any {
 		return TeamsResponse{
 			Teams:      values,
 			TotalCount: totalCount,
@@ -35,7 +50,15 @@ func (h *TeamHandler) GetPaged(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *TeamHandler) GetByID(w http.ResponseWriter, r *http.Request) {
-	GetByID(w, r, h.TeamRepo)
+	// Load data
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+
+	// Respond
+	team.Name = team.NameFor(middleware.ExtractOptionalUserAuth(r))
+	RespondVisible(w, r, team)
 }
 
 func (h *TeamHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
@@ -53,7 +76,7 @@ func (h *TeamHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Respond
-	utils.RespondWithJSON(w, members)
+	RespondVisible(w, r, members)
 }
 
 func (h *TeamHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -74,22 +97,69 @@ func (h *TeamHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check team rules
+	settings, err := h.SettingsRepo.Get(r.Context())
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+	founder, err := h.UserRepo.GetByID(r.Context(), userAuth.ID)
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+	if checkViolations(w, teamrules.CheckJoin(&settings.TeamRules, []models.User{*founder}, time.Now())) {
+		return
+	}
+
+	// Check name
+	teamID := bson.NewObjectID()
+	nameKey, ok := h.checkName(w, r, settings, request.Name, teamID)
+	if !ok {
+		return
+	}
+	nameStatus := models.NameApproved
+	if settings.TeamNameApproval && userAuth.Role != models.Admin {
+		nameStatus = models.NamePending
+	}
+
+	// Take a place among the teams, the counter makes the limit hold under concurrent requests
+	reserved, err := h.CounterRepo.Reserve(r.Context(), repository.TeamsCounter, settings.TeamRules.MaxTeams)
+	if err == nil && !reserved {
+		h.TeamRepo.ReleaseNameKey(r.Context(), nameKey, teamID)
+		checkViolations(w, []teamrules.Violation{teamrules.TooManyTeams(&settings.TeamRules)})
+		return
+	}
+	if utils.CheckError(w, err, "Failed to count teams", http.StatusInternalServerError) {
+		h.TeamRepo.ReleaseNameKey(r.Context(), nameKey, teamID)
+		return
+	}
+
 	// Do work
 	createdID, err := h.TeamRepo.Create(r.Context(), &models.Team{
+		ID:              teamID,
 		Name:            request.Name,
+		NameKey:         nameKey,
+		NameStatus:      nameStatus,
 		Leader:          userAuth.ID,
 		Repos:           make([]string, 0),
 		PresentationURI: "",
-		Grades:          make(models.Grades),
 	})
 	if utils.CheckError(w, err, "Failed to create", http.StatusInternalServerError) {
+		h.CounterRepo.Release(r.Context(), repository.TeamsCounter)
+		h.TeamRepo.ReleaseNameKey(r.Context(), nameKey, teamID)
 		return
 	}
+	h.CounterRepo.Set(r.Context(), repository.MembersCounter(createdID), 1)
 	h.UserRepo.Update(r.Context(), userAuth.ID, bson.M{
-		"team": createdID,
+		"team":      createdID,
+		"joined_at": time.Now(),
 	})
+	revokeSessions(r.Context(), h.SessionRepo, userAuth.ID)
 
 	// Respond
+	if nameStatus == models.NamePending {
+		fmt.Fprintf(w, "Successfully created, the name is waiting for approval")
+		return
+	}
 	fmt.Fprintf(w, "Successfully created")
 }
 
@@ -119,27 +189,126 @@ func (h *TeamHandler) Update(w http.ResponseWriter, r *http.Request) {
 		Leader          bson.ObjectID `json:"leader" bson:"leader,omitempty" validate:"omitempty,owner"`
 		Repos           []string      `json:"repos" bson:"repos,omitempty" validate:"omitempty,owner,dive,url"`
 		PresentationURI string        `json:"presentation_uri" bson:"presentation_uri,omitempty" validate:"omitempty,owner,url"`
-		Grades          models.Grades `json:"grades" bson:"grades,omitempty" validate:"omitempty,judge,grades"`
 	}
 	if ParseAndValidate(w, r, validators.NewTeamValidator(userAuth, team), &request) {
 		return
 	}
 
+	// The project links are frozen together with the submissions
+	if (len(request.Repos) > 0 || request.PresentationURI != "") && userAuth.Role != models.Admin {
+		settings, err := h.SettingsRepo.Get(r.Context())
+		if utils.CheckGetFromDB(w, err) {
+			return
+		}
+		if lock := settings.SubmissionLock(); !lock.IsZero() && time.Now().After(lock) {
+			http.Error(w, "Access denied: submissions are locked", http.StatusForbidden)
+			return
+		}
+	}
+
+	// Pushes are credited by repository, so a repository can only belong to one team
+	if len(request.Repos) > 0 && h.checkRepos(w, r, team, request.Repos) {
+		return
+	}
+
+	// The leader can only be handed over to a member
+	var newLeader *models.User
+	if !request.Leader.IsZero() && request.Leader != team.Leader {
+		newLeader, err = h.UserRepo.GetByID(r.Context(), request.Leader)
+		if utils.CheckGetFromDB(w, err) {
+			return
+		}
+		if newLeader.Team != team.ID {
+			http.Error(w, "The new leader must be a member of the team", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Names go through moderation
+	if request.Name != "" && request.Name != team.Name {
+		if !h.rename(w, r, team, request.Name, userAuth) {
+			return
+		}
+	}
+	request.Name = ""
+
+	// Hand over the leadership the same way as the transfer endpoint, so the new leader is notified
+	if newLeader != nil {
+		err = h.membership().setLeader(r.Context(), team, newLeader)
+		if utils.CheckError(w, err, "Failed to transfer leadership", http.StatusInternalServerError) {
+			return
+		}
+	}
+	request.Leader = bson.NilObjectID
+
 	UpdateInner(w, r, h.TeamRepo, parsedId, request)
 }
 
 func (h *TeamHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	Delete(w, r, h.TeamRepo, func(w http.ResponseWriter, r *http.Request, id bson.ObjectID, userAuth *models.User) bool {
-		team, err := h.TeamRepo.GetByID(r.Context(), id)
-		if utils.CheckGetFromDB(w, err) {
-			return true
+	// Load data
+	var parsedId bson.ObjectID
+	var exit bool
+	if parsedId, exit = utils.ParseRequestID(w, r); exit {
+		return
+	}
+
+	team, err := h.TeamRepo.GetByID(r.Context(), parsedId)
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+
+	// Check access
+	userAuth := middleware.ExtractUserAuth(r)
+	if userAuth.Role != models.Admin && team.Leader != userAuth.ID {
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return
+	}
+
+	// The members are unlinked by the delete, so they are loaded first
+	members, err := h.TeamRepo.GetMembers(r.Context(), parsedId)
+	if utils.CheckError(w, err, "Failed to get members", http.StatusInternalServerError) {
+		return
+	}
+
+	// Do work
+	err = h.TeamRepo.Delete(r.Context(), parsedId)
+	if utils.CheckError(w, err, "Failed to delete", http.StatusInternalServerError) {
+		return
+	}
+
+	// Members lose their team claim, revoking only after the write so a refresh can't re-issue it
+	for _, member := range members {
+		revokeSessions(r.Context(), h.SessionRepo, member.ID)
+	}
+	h.Notifier.SendToUsers(members, fmt.Sprintf("Команда «%s» расформирована", team.Name))
+
+	// Respond
+	fmt.Fprintf(w, "Successfully deleted")
+}
+
+// checkRepos rejects repositories already linked to another team
+func (h *TeamHandler) checkRepos(w http.ResponseWriter, r *http.Request, team *models.Team, repos []string) bool {
+	teams, err := h.TeamRepo.Find(r.Context())
+	if utils.CheckError(w, err, "Failed to get teams", http.StatusInternalServerError) {
+		return true
+	}
+
+	taken := make(map[string]bool)
+	for _, other := range teams {
+		if other.ID == team.ID {
+			continue
 		}
+		for _, repo := range other.Repos {
+			taken[webhook.NormalizeRepoURL(repo)] = true
+		}
+	}
 
-		if userAuth.Role == models.Admin || team.Leader == userAuth.ID {
-			return false
-		} else {
-			http.Error(w, "Access denied", http.StatusForbidden)
+	for _, repo := range repos {
+		if taken[webhook.NormalizeRepoURL(repo)] {
+			http.Error(w, "Repository is already linked to another team: "+repo, http.StatusConflict)
 			return true
 		}
-	})
+	}
+
+	return false
 }
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/internal/middleware"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func (h *TeamHandler) SelectCase(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+
+	// Check access
+	if h.caseSelectionCheck(w, r, team) {
+		return
+	}
+
+	// Parse
+	var request struct {
+		CaseID bson.ObjectID `json:"case_id" validate:"required"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	if team.Case == request.CaseID {
+		fmt.Fprintf(w, "Successfully updated")
+		return
+	}
+
+	selected, err := h.CaseRepo.GetByID(r.Context(), request.CaseID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		http.Error(w, "Case not found", http.StatusNotFound)
+		return
+	} else if utils.CheckError(w, err, "Failed to get case from DB", http.StatusInternalServerError) {
+		return
+	}
+
+	// Take a place in the case, the counter makes the capacity hold under concurrent requests
+	counter := repository.CaseTeamsCounter(selected.ID)
+	reserved, err := h.CounterRepo.Reserve(r.Context(), counter, selected.Capacity)
+	if utils.CheckError(w, err, "Failed to count teams", http.StatusInternalServerError) {
+		return
+	}
+	if !reserved {
+		http.Error(w, "The case has no free places left", http.StatusConflict)
+		return
+	}
+
+	// Do work
+	if !h.moveCase(w, r, team, selected.ID) {
+		h.CounterRepo.Release(r.Context(), counter)
+		return
+	}
+
+	// Respond
+	fmt.Fprintf(w, "Successfully updated")
+}
+
+func (h *TeamHandler) ClearCase(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+
+	// Check access
+	if h.caseSelectionCheck(w, r, team) {
+		return
+	}
+
+	// Do work
+	if !team.Case.IsZero() && !h.moveCase(w, r, team, bson.NilObjectID) {
+		return
+	}
+
+	// Respond
+	fmt.Fprintf(w, "Successfully updated")
+}
+
+// moveCase switches the case of the team and gives the place in the previous case back
+func (h *TeamHandler) moveCase(w http.ResponseWriter, r *http.Request, team *models.Team, caseID bson.ObjectID) bool {
+	moved, err := h.TeamRepo.SetCase(r.Context(), team.ID, team.Case, caseID)
+	if utils.CheckError(w, err, "Failed to update", http.StatusInternalServerError) {
+		return false
+	}
+	if !moved {
+		http.Error(w, "The case of the team was changed by another request, try again", http.StatusConflict)
+		return false
+	}
+
+	if !team.Case.IsZero() {
+		h.CounterRepo.Release(r.Context(), repository.CaseTeamsCounter(team.Case))
+	}
+
+	return true
+}
+
+// caseSelectionCheck lets the leader pick a case until the deadline, admins at any time
+func (h *TeamHandler) caseSelectionCheck(w http.ResponseWriter, r *http.Request, team *models.Team) bool {
+	userAuth := middleware.ExtractUserAuth(r)
+	if userAuth.Role == models.Admin {
+		return false
+	}
+
+	if team.Leader != userAuth.ID {
+		http.Error(w, "Access denied: only the team leader can select a case", http.StatusForbidden)
+		return true
+	}
+
+	settings, err := h.SettingsRepo.Get(r.Context())
+	if utils.CheckGetFromDB(w, err) {
+		return true
+	}
+
+	if !settings.CaseSelectionDeadline.IsZero() && time.Now().After(settings.CaseSelectionDeadline) {
+		http.Error(w, "Access denied: the case selection deadline has passed", http.StatusForbidden)
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/internal"
+	"github.com/SomeSuperCoder/global-chat/internal/middleware"
+	"github.com/SomeSuperCoder/global-chat/internal/notify"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func (h *TeamHandler) Leave(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.UserRepo.GetByID(r.Context(), middleware.ExtractUserAuth(r).ID)
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+
+	// Check access
+	if user.Team != team.ID {
+		http.Error(w, "Access denied: you are not a member of this team", http.StatusForbidden)
+		return
+	}
+
+	// Do work
+	err = h.membership().remove(r.Context(), team, user)
+	if utils.CheckError(w, err, "Failed to leave team", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	fmt.Fprintf(w, "Successfully left")
+}
+
+func (h *TeamHandler) Kick(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+
+	memberID, err := bson.ObjectIDFromHex(r.PathValue("userId"))
+	if err != nil {
+		http.Error(w, "Invalid user ID provided", http.StatusBadRequest)
+		return
+	}
+
+	// Get auth data
+	userAuth := middleware.ExtractUserAuth(r)
+
+	// Check access
+	if userAuth.Role != models.Admin && team.Leader != userAuth.ID {
+		http.Error(w, "Access denied: only the team leader can remove members", http.StatusForbidden)
+		return
+	}
+	if memberID == userAuth.ID {
+		http.Error(w, "Use leave to remove yourself", http.StatusBadRequest)
+		return
+	}
+
+	member, err := h.UserRepo.GetByID(r.Context(), memberID)
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+	if member.Team != team.ID {
+		http.Error(w, "User is not a member of this team", http.StatusNotFound)
+		return
+	}
+
+	// Do work
+	err = h.membership().remove(r.Context(), team, member)
+	if utils.CheckError(w, err, "Failed to remove member", http.StatusInternalServerError) {
+		return
+	}
+	h.Notifier.Send(member.ChatID, fmt.Sprintf("Вас исключили из команды «%s»", team.Name))
+
+	// Respond
+	fmt.Fprintf(w, "Successfully removed")
+}
+
+func (h *TeamHandler) TransferLeadership(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+
+	// Check access
+	userAuth := middleware.ExtractUserAuth(r)
+	if userAuth.Role != models.Admin && team.Leader != userAuth.ID {
+		http.Error(w, "Access denied: only the team leader can transfer leadership", http.StatusForbidden)
+		return
+	}
+
+	// Parse
+	var request struct {
+		UserID bson.ObjectID `json:"user_id" validate:"required"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	newLeader, err := h.UserRepo.GetByID(r.Context(), request.UserID)
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+	if newLeader.Team != team.ID {
+		http.Error(w, "The new leader must be a member of the team", http.StatusBadRequest)
+		return
+	}
+
+	// Do work
+	err = h.membership().setLeader(r.Context(), team, newLeader)
+	if utils.CheckError(w, err, "Failed to transfer leadership", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	fmt.Fprintf(w, "Successfully transferred")
+}
+
+func (h *TeamHandler) loadTeam(w http.ResponseWriter, r *http.Request) (*models.Team, bool) {
+	var parsedId bson.ObjectID
+	var exit bool
+	if parsedId, exit = utils.ParseRequestID(w, r); exit {
+		return nil, false
+	}
+
+	team, err := h.TeamRepo.GetByID(r.Context(), parsedId)
+	if utils.CheckGetFromDB(w, err) {
+		return nil, false
+	}
+
+	return team, true
+}
+
+func (h *TeamHandler) membership() *membership {
+	return &membership{
+		teamRepo:    h.TeamRepo,
+		userRepo:    h.UserRepo,
+		sessionRepo: h.SessionRepo,
+		counterRepo: h.CounterRepo,
+		notifier:    h.Notifier,
+	}
+}
+
+// ===================================================
+// Membership changes shared by team and user handlers
+// ===================================================
+type membership struct {
+	teamRepo    *repository.TeamRepo
+	userRepo    *repository.UserRepo
+	sessionRepo *repository.SessionRepo
+	counterRepo *repository.CounterRepo
+	notifier    *notify.Notifier
+}
+
+// remove takes the user out of the team. If the leader leaves, the longest standing
+// member takes over, and a team left without members is disbanded.
+// Falling below MinMembers is not blocked, so nobody gets stuck in a team:
+// the team is flagged by the compliance report instead.
+func (m *membership) remove(ctx context.Context, team *models.Team, user *models.User) error {
+	err := m.userRepo.Update(ctx, user.ID, bson.M{
+		"team": internal.UndefinedObjectID,
+	})
+	if err != nil {
+		return err
+	}
+	m.counterRepo.Release(ctx, repository.MembersCounter(team.ID))
+	revokeSessions(ctx, m.sessionRepo, user.ID)
+
+	remaining, err := m.teamRepo.GetMembers(ctx, team.ID)
+	if err != nil {
+		return err
+	}
+
+	if team.Leader != user.ID {
+		m.notifier.SendToUsers(remaining, fmt.Sprintf("%s покинул(а) команду «%s»", user.Name, team.Name))
+		return nil
+	}
+
+	if len(remaining) == 0 {
+		return m.teamRepo.Delete(ctx, team.ID)
+	}
+
+	successor := remaining[0]
+	for _, member := range remaining[1:] {
+		if joinedAt(&member).Before(joinedAt(&successor)) {
+			successor = member
+		}
+	}
+
+	m.notifier.SendToUsers(remaining, fmt.Sprintf("Лидер %s покинул(а) команду «%s»", user.Name, team.Name))
+	return m.setLeader(ctx, team, &successor)
+}
+
+// joinedAt falls back to the registration time for members who joined before it was recorded
+func joinedAt(user *models.User) time.Time {
+	if user.JoinedAt.IsZero() {
+		return user.ID.Timestamp()
+	}
+
+	return user.JoinedAt
+}
+
+func (m *membership) setLeader(ctx context.Context, team *models.Team, leader *models.User) error {
+	err := m.teamRepo.Update(ctx, team.ID, bson.M{
+		"leader": leader.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	m.notifier.Send(leader.ChatID, fmt.Sprintf("Теперь вы лидер команды «%s»", team.Name))
+	return nil
+}
+
+// leaveCurrentTeam runs the membership rules before the user is moved or deleted
+func (m *membership) leaveCurrentTeam(ctx context.Context, user *models.User) error {
+	if user.Team.IsZero() || user.Team == internal.UndefinedObjectID {
+		return nil
+	}
+
+	team, err := m.teamRepo.GetByID(ctx, user.Team)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return m.remove(ctx, team, user)
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/SomeSuperCoder/global-chat/internal/moderation"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var errNameTaken = errors.New("Team name is already taken")
+
+func (h *TeamHandler) GetPendingNames(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Load data
+	teams, err := h.TeamRepo.FindPendingNames(r.Context())
+	if utils.CheckError(w, err, "Failed to get teams", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	RespondVisible(w, r, teams)
+}
+
+func (h *TeamHandler) ApproveName(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Load data
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+
+	// Do work
+	var err error
+	name := team.Name
+	switch {
+	case team.PendingName != "":
+		name = team.PendingName
+		err = h.TeamRepo.UnsetPendingName(r.Context(), team.ID, bson.M{
+			"name":        team.PendingName,
+			"name_key":    team.PendingNameKey,
+			"name_status": models.NameApproved,
+		})
+		if err == nil {
+			h.releaseNameKeys(r.Context(), team.ID, team.PendingNameKey, team.NameKey)
+		}
+	case team.NameStatus == models.NamePending || team.NameStatus == models.NameRejected:
+		err = h.TeamRepo.Update(r.Context(), team.ID, bson.M{
+			"name_status": models.NameApproved,
+		})
+	default:
+		http.Error(w, "The team name is not waiting for approval", http.StatusConflict)
+		return
+	}
+	if utils.CheckError(w, err, "Failed to update", http.StatusInternalServerError) {
+		return
+	}
+
+	h.notifyTeam(r.Context(), team.ID, fmt.Sprintf("Название команды «%s» одобрено", name))
+
+	// Respond
+	fmt.Fprintf(w, "Successfully approved")
+}
+
+func (h *TeamHandler) RejectName(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Load data
+	team, ok := h.loadTeam(w, r)
+	if !ok {
+		return
+	}
+
+	// Parse
+	var request struct {
+		Reason string `json:"reason" validate:"max=500"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	// Do work
+	var err error
+	name := team.Name
+	switch {
+	case team.PendingName != "":
+		// The old approved name stays
+		name = team.PendingName
+		err = h.TeamRepo.UnsetPendingName(r.Context(), team.ID, bson.M{
+			"name_status": models.NameApproved,
+		})
+		if err == nil {
+			h.releaseNameKeys(r.Context(), team.ID, team.NameKey, team.PendingNameKey)
+		}
+	case team.NameStatus == models.NamePending:
+		err = h.TeamRepo.Update(r.Context(), team.ID, bson.M{
+			"name_status": models.NameRejected,
+		})
+	default:
+		http.Error(w, "The team name is not waiting for approval", http.StatusConflict)
+		return
+	}
+	if utils.CheckError(w, err, "Failed to update", http.StatusInternalServerError) {
+		return
+	}
+
+	message := fmt.Sprintf("Название команды «%s» отклонено модератором", name)
+	if reason := strings.TrimSpace(request.Reason); reason != "" {
+		message += ". Причина: " + reason
+	}
+	h.notifyTeam(r.Context(), team.ID, message+". Пожалуйста, выберите другое название")
+
+	// Respond
+	fmt.Fprintf(w, "Successfully rejected")
+}
+
+// checkName rejects banned names and reserves the uniqueness key of the name for the team.
+// The caller releases the key if the name doesn't get stored.
+func (h *TeamHandler) checkName(w http.ResponseWriter, r *http.Request, settings *models.Settings, name string, teamID bson.ObjectID) (string, bool) {
+	if banned := moderation.FindBanned(name, settings.BannedWords); len(banned) > 0 {
+		http.Error(w, "The team name contains banned words", http.StatusBadRequest)
+		return "", false
+	}
+
+	key := moderation.NameKey(name)
+	reserved, err := h.TeamRepo.ReserveNameKey(r.Context(), key, teamID)
+	if utils.CheckError(w, err, "Failed to check the team name", http.StatusInternalServerError) {
+		return "", false
+	}
+	if !reserved {
+		http.Error(w, errNameTaken.Error(), http.StatusConflict)
+		return "", false
+	}
+
+	return key, true
+}
+
+// rename changes the team name right away or queues it for approval
+func (h *TeamHandler) rename(w http.ResponseWriter, r *http.Request, team *models.Team, name string, userAuth *models.User) bool {
+	settings, err := h.SettingsRepo.Get(r.Context())
+	if utils.CheckGetFromDB(w, err) {
+		return false
+	}
+
+	key, ok := h.checkName(w, r, settings, name, team.ID)
+	if !ok {
+		return false
+	}
+
+	needsApproval := settings.TeamNameApproval && userAuth.Role != models.Admin
+	// Keys of the team that stop being used by this rename
+	var released []string
+	if needsApproval && team.NameApproved() {
+		// Keep the approved name public until the new one is reviewed
+		err = h.TeamRepo.Update(r.Context(), team.ID, bson.M{
+			"pending_name":     name,
+			"pending_name_key": key,
+		})
+		released = []string{team.PendingNameKey}
+	} else {
+		status := models.NameApproved
+		if needsApproval {
+			status = models.NamePending
+		}
+		err = h.TeamRepo.UnsetPendingName(r.Context(), team.ID, bson.M{
+			"name":        name,
+			"name_key":    key,
+			"name_status": status,
+		})
+		released = []string{team.NameKey, team.PendingNameKey}
+	}
+	if utils.CheckError(w, err, "Failed to update", http.StatusInternalServerError) {
+		h.releaseNameKeys(r.Context(), team.ID, team.NameKey, key)
+		return false
+	}
+
+	h.releaseNameKeys(r.Context(), team.ID, key, released...)
+	return true
+}
+
+// releaseNameKeys frees the keys the team doesn't use anymore, except the one it still holds
+func (h *TeamHandler) releaseNameKeys(ctx context.Context, teamID bson.ObjectID, kept string, keys ...string) {
+	for _, key := range keys {
+		if key != kept {
+			h.TeamRepo.ReleaseNameKey(ctx, key, teamID)
+		}
+	}
+}
+
+// hideNames replaces the names the viewer may not see yet
+func hideNames(teams []models.Team, viewer *models.User) []models.Team {
+	for i := range teams {
+		teams[i].Name = teams[i].NameFor(viewer)
+	}
+
+	return teams
+}
+
+func (h *TeamHandler) notifyTeam(ctx context.Context, teamID bson.ObjectID, text string) {
+	members, err := h.TeamRepo.GetMembers(ctx, teamID)
+	if err != nil {
+		return
+	}
+
+	h.Notifier.SendToUsers(members, text)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/internal/teamrules"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type ComplianceResponse struct {
+	Rules      models.TeamRules      `json:"rules"`
+	TeamCount  int64                 `json:"team_count"`
+	Violations []teamrules.Violation `json:"violations"`
+	Teams      []TeamCompliance      `json:"teams"`
+}
+
+type TeamCompliance struct {
+	Team        bson.ObjectID         `json:"team"`
+	Name        string                `json:"name"`
+	MemberCount int                   `json:"member_count"`
+	Violations  []teamrules.Violation `json:"violations"`
+}
+
+func (h *TeamHandler) GetCompliance(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Load data
+	settings, err := h.SettingsRepo.Get(r.Context())
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+
+	teams, err := h.TeamRepo.Find(r.Context())
+	if utils.CheckError(w, err, "Failed to get teams", http.StatusInternalServerError) {
+		return
+	}
+
+	users, err := h.UserRepo.Find(r.Context())
+	if utils.CheckError(w, err, "Failed to get users", http.StatusInternalServerError) {
+		return
+	}
+
+	// Do work
+	members := make(map[bson.ObjectID][]models.User)
+	for _, user := range users {
+		members[user.Team] = append(members[user.Team], user)
+	}
+
+	response := ComplianceResponse{
+		Rules:      settings.TeamRules,
+		TeamCount:  int64(len(teams)),
+		Violations: []teamrules.Violation{},
+		Teams:      []TeamCompliance{},
+	}
+	if settings.TeamRules.MaxTeams > 0 && len(teams) > settings.TeamRules.MaxTeams {
+		response.Violations = teamrules.CheckTeamCount(&settings.TeamRules, int64(len(teams)))
+	}
+
+	now := time.Now()
+	for _, team := range teams {
+		violations := teamrules.Check(&settings.TeamRules, members[team.ID], now)
+		if len(violations) == 0 {
+			continue
+		}
+
+		response.Teams = append(response.Teams, TeamCompliance{
+			Team:        team.ID,
+			Name:        team.Name,
+			MemberCount: len(members[team.ID]),
+			Violations:  violations,
+		})
+	}
+
+	// Respond
+	utils.RespondWithJSON(w, response)
+}
+
+// checkJoin rejects a membership change that would break the team rules
+func checkJoin(w http.ResponseWriter, r *http.Request, settingsRepo *repository.SettingsRepo, teamRepo *repository.TeamRepo, teamID bson.ObjectID, user *models.User) bool {
+	settings, err := settingsRepo.Get(r.Context())
+	if utils.CheckGetFromDB(w, err) {
+		return true
+	}
+
+	_, err = teamRepo.GetByID(r.Context(), teamID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		http.Error(w, "Team not found", http.StatusNotFound)
+		return true
+	} else if utils.CheckError(w, err, "Failed to get team from DB", http.StatusInternalServerError) {
+		return true
+	}
+
+	members, err := teamRepo.GetMembers(r.Context(), teamID)
+	if utils.CheckError(w, err, "Failed to get members", http.StatusInternalServerError) {
+		return true
+	}
+
+	return checkViolations(w, teamrules.CheckJoin(&settings.TeamRules, append(members, *user), time.Now()))
+}
+
+func checkViolations(w http.ResponseWriter, violations []teamrules.Violation) bool {
+	if len(violations) > 0 {
+		http.Error(w, "Team rules violated: "+violations[0].Message, http.StatusConflict)
+		return true
+	}
+
+	return false
+}
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/SomeSuperCoder/global-chat/internal"
 	"github.com/SomeSuperCoder/global-chat/internal/middleware"
+	"github.com/SomeSuperCoder/global-chat/internal/notify"
+	"github.com/SomeSuperCoder/global-chat/internal/teamrules"
 	"github.com/SomeSuperCoder/global-chat/internal/validators"
 	"github.com/SomeSuperCoder/global-chat/models"
 	"github.com/SomeSuperCoder/global-chat/repository"
@@ -13,7 +17,12 @@ import (
 )
 
 type UserHandler struct {
-	Repo *repository.UserRepo
+	Repo         *repository.UserRepo
+	TeamRepo     *repository.TeamRepo
+	SessionRepo  *repository.SessionRepo
+	SettingsRepo *repository.SettingsRepo
+	CounterRepo  *repository.CounterRepo
+	Notifier     *notify.Notifier
 }
 
 type UsersResponse struct {
@@ -42,7 +51,7 @@ func (h *UserHandler) GetByUsername(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	utils.RespondWithJSON(w, user)
+	RespondVisible(w, r, user)
 }
 
 func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
@@ -62,18 +71,80 @@ func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
 		Birthdate time.Time       `json:"birthdate" bson:"birthdate,omitempty" validate:"omitempty,self"`
 		Role      models.UserRole `json:"role" bson:"role,omitempty" validate:"omitempty,admin,oneof=0 1 2"`
 		Team      bson.ObjectID   `json:"team" bson:"team,omitempty" validate:"omitempty,self"`
+		JoinedAt  time.Time       `json:"-" bson:"joined_at,omitempty"`
 	}
 
 	if ParseAndValidate(w, r, validators.NewUserValidator(userAuth, parsedId), &request) {
 		return
 	}
 
-	UpdateInner(w, r, h.Repo, parsedId, request)
+	// The team being joined, its place is given back if the update fails
+	var joining bson.ObjectID
+	if !request.Team.IsZero() {
+		user, err := h.Repo.GetByID(r.Context(), parsedId)
+		if utils.CheckGetFromDB(w, err) {
+			return
+		}
+
+		if user.Team != request.Team {
+			// Joining a team has to respect the team rules
+			if request.Team != internal.UndefinedObjectID {
+				if checkJoin(w, r, h.SettingsRepo, h.TeamRepo, request.Team, user) {
+					return
+				}
+				if !h.reserveMember(w, r, request.Team) {
+					return
+				}
+				joining = request.Team
+				request.JoinedAt = time.Now()
+			}
+
+			// Leaving the current team may hand over leadership or disband it
+			err = h.membership().leaveCurrentTeam(r.Context(), user)
+			if err != nil && !joining.IsZero() {
+				h.CounterRepo.Release(r.Context(), repository.MembersCounter(joining))
+			}
+			if utils.CheckError(w, err, "Failed to leave team", http.StatusInternalServerError) {
+				return
+			}
+		}
+	}
+
+	// Do work
+	err := h.Repo.Update(r.Context(), parsedId, request)
+	if err != nil && !joining.IsZero() {
+		h.CounterRepo.Release(r.Context(), repository.MembersCounter(joining))
+	}
+	if utils.CheckError(w, err, "Failed to update", http.StatusInternalServerError) {
+		return
+	}
+
+	// Role and team are part of the session claims.
+	// Revoking only after the write makes sure a refresh can't re-issue the old values.
+	if request.Role != 0 || !request.Team.IsZero() {
+		revokeSessions(r.Context(), h.SessionRepo, parsedId)
+	}
+
+	// Respond
+	fmt.Fprintf(w, "Successfully updated")
 }
 
 func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	Delete(w, r, h.Repo, func(w http.ResponseWriter, r *http.Request, id bson.ObjectID, userAuth *models.User) bool {
 		if userAuth.Role == models.Admin || id == userAuth.ID {
+			user, err := h.Repo.GetByID(r.Context(), id)
+			if utils.CheckGetFromDB(w, err) {
+				return true
+			}
+
+			// Don't leave the team with a missing leader
+			err = h.membership().leaveCurrentTeam(r.Context(), user)
+			if utils.CheckError(w, err, "Failed to leave team", http.StatusInternalServerError) {
+				return true
+			}
+
+			revokeSessions(r.Context(), h.SessionRepo, id)
+			h.SessionRepo.DeleteByUser(r.Context(), id)
 			return false
 		} else {
 			http.Error(w, "Access denied", http.StatusForbidden)
@@ -81,3 +152,32 @@ func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		}
 	})
 }
+
+// reserveMember takes a place in the team, the counter makes MaxMembers hold under concurrent joins
+func (h *UserHandler) reserveMember(w http.ResponseWriter, r *http.Request, teamID bson.ObjectID) bool {
+	settings, err := h.SettingsRepo.Get(r.Context())
+	if utils.CheckGetFromDB(w, err) {
+		return false
+	}
+
+	reserved, err := h.CounterRepo.Reserve(r.Context(), repository.MembersCounter(teamID), settings.TeamRules.MaxMembers)
+	if utils.CheckError(w, err, "Failed to count members", http.StatusInternalServerError) {
+		return false
+	}
+	if !reserved {
+		checkViolations(w, []teamrules.Violation{teamrules.TeamFull(&settings.TeamRules)})
+		return false
+	}
+
+	return true
+}
+
+func (h *UserHandler) membership() *membership {
+	return &membership{
+		teamRepo:    h.TeamRepo,
+		userRepo:    h.Repo,
+		sessionRepo: h.SessionRepo,
+		counterRepo: h.CounterRepo,
+		notifier:    h.Notifier,
+	}
+}
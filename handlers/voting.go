@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/internal/middleware"
+	"github.com/SomeSuperCoder/global-chat/internal/voting"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type VotingHandler struct {
+	SettingsRepo *repository.SettingsRepo
+	TeamRepo     *repository.TeamRepo
+	VoteRepo     *repository.VoteRepo
+}
+
+type VoteAuditResponse struct {
+	Digest string        `json:"digest"`
+	Votes  []models.Vote `json:"votes"`
+}
+
+// GetBallot lists the teams the user can vote for
+func (h *VotingHandler) GetBallot(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	ballot, err := voting.LoadBallot(r.Context(), h.SettingsRepo, h.TeamRepo, h.VoteRepo, middleware.ExtractUserAuth(r))
+	if utils.CheckError(w, err, "Failed to load the ballot", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	utils.RespondWithJSON(w, ballot)
+}
+
+func (h *VotingHandler) Vote(w http.ResponseWriter, r *http.Request) {
+	// Parse
+	var request struct {
+		Team bson.ObjectID `json:"team" validate:"required"`
+	}
+	if DefaultParseAndValidate(w, r, &request) {
+		return
+	}
+
+	// Do work
+	vote, _, err := voting.Cast(r.Context(), h.SettingsRepo, h.TeamRepo, h.VoteRepo, middleware.ExtractUserAuth(r), request.Team)
+	switch {
+	case errors.Is(err, voting.ErrClosed), errors.Is(err, voting.ErrOwnTeam), errors.Is(err, voting.ErrNotParticipant):
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	case errors.Is(err, repository.ErrAlreadyVoted), errors.Is(err, repository.ErrNoVotesLeft):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case errors.Is(err, mongo.ErrNoDocuments):
+		http.Error(w, "Team not found", http.StatusNotFound)
+		return
+	}
+	if utils.CheckError(w, err, "Failed to vote", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	RespondVisible(w, r, vote)
+}
+
+// GetResults shows the vote count of every team to admins and to everyone once voting is over
+func (h *VotingHandler) GetResults(w http.ResponseWriter, r *http.Request) {
+	// Load data
+	settings, err := h.SettingsRepo.Get(r.Context())
+	if utils.CheckGetFromDB(w, err) {
+		return
+	}
+
+	// Check access
+	userAuth := middleware.ExtractOptionalUserAuth(r)
+	final := settings.VotingFinished(time.Now())
+	if !final && (userAuth == nil || userAuth.Role != models.Admin) {
+		http.Error(w, "Access denied: voting is not over yet", http.StatusForbidden)
+		return
+	}
+
+	// Do work
+	teams, err := h.TeamRepo.Find(r.Context())
+	if utils.CheckError(w, err, "Failed to get teams", http.StatusInternalServerError) {
+		return
+	}
+	votes, err := h.VoteRepo.Find(r.Context())
+	if utils.CheckError(w, err, "Failed to get votes", http.StatusInternalServerError) {
+		return
+	}
+
+	results := voting.Tally(teams, votes, userAuth)
+	results.Final = final
+
+	// Respond
+	utils.RespondWithJSON(w, results)
+}
+
+// GetAudit returns every vote with the digest published along with the results
+func (h *VotingHandler) GetAudit(w http.ResponseWriter, r *http.Request) {
+	// Check access
+	if AdminCheck(w, r) {
+		return
+	}
+
+	// Load data
+	votes, err := h.VoteRepo.Find(r.Context())
+	if utils.CheckError(w, err, "Failed to get votes", http.StatusInternalServerError) {
+		return
+	}
+
+	// Respond
+	RespondVisible(w, r, VoteAuditResponse{
+		Digest: voting.Digest(votes),
+		Votes:  votes,
+	})
+}
@@ -0,0 +1,207 @@
+package assignment
+
+import (
+	"sort"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ConflictSet tells which judges may not grade which teams
+type ConflictSet map[bson.ObjectID]map[bson.ObjectID]bool
+
+// Conflicts resolves the declared conflicts into judge-team pairs.
+// Conflicts with a participant apply to their current team, judges never grade their own team.
+func Conflicts(conflicts []models.Conflict, users []models.User) ConflictSet {
+	teamOf := make(map[bson.ObjectID]bson.ObjectID, len(users))
+	set := make(ConflictSet)
+	for _, user := range users {
+		teamOf[user.ID] = user.Team
+		if user.Role == models.Judge && !user.Team.IsZero() {
+			set.add(user.ID, user.Team)
+		}
+	}
+
+	for _, conflict := range conflicts {
+		if !conflict.Team.IsZero() {
+			set.add(conflict.Judge, conflict.Team)
+		}
+		if team, ok := teamOf[conflict.User]; ok && !conflict.User.IsZero() && !team.IsZero() {
+			set.add(conflict.Judge, team)
+		}
+	}
+
+	return set
+}
+
+func (s ConflictSet) add(judge, team bson.ObjectID) {
+	if s[judge] == nil {
+		s[judge] = make(map[bson.ObjectID]bool)
+	}
+	s[judge][team] = true
+}
+
+func (s ConflictSet) Has(judge, team bson.ObjectID) bool {
+	return s[judge][team]
+}
+
+type Options struct {
+	// Judges every team should be seen by
+	PerTeam int
+	// Give every case its own group of judges, so a judge compares teams working on the same task
+	ByCase bool
+}
+
+type Shortage struct {
+	Team     bson.ObjectID `json:"team"`
+	Assigned int           `json:"assigned"`
+	Wanted   int           `json:"wanted"`
+}
+
+type Result struct {
+	Assignments []models.Assignment   `json:"-"`
+	Load        map[bson.ObjectID]int `json:"load"`
+	Shortages   []Shortage            `json:"shortages"`
+}
+
+// Generate spreads the teams across the judges so every team gets PerTeam judges
+// and the load stays even, skipping conflicted pairs
+func Generate(teams []models.Team, judges []models.User, conflicts ConflictSet, options Options) Result {
+	result := Result{
+		Assignments: []models.Assignment{},
+		Load:        make(map[bson.ObjectID]int, len(judges)),
+		Shortages:   []Shortage{},
+	}
+	for _, judge := range judges {
+		result.Load[judge.ID] = 0
+	}
+
+	all := make([]bson.ObjectID, 0, len(judges))
+	for _, judge := range judges {
+		all = append(all, judge.ID)
+	}
+
+	groups := [][]models.Team{teams}
+	pools := [][]bson.ObjectID{all}
+	if options.ByCase {
+		groups, pools = caseGroups(teams, all, options.PerTeam)
+	}
+
+	now := time.Now()
+	for i, group := range groups {
+		pool := pools[i]
+
+		// The teams with the fewest eligible judges pick first
+		group = append([]models.Team{}, group...)
+		eligible := func(team bson.ObjectID) int {
+			count := 0
+			for _, judge := range pool {
+				if !conflicts.Has(judge, team) {
+					count++
+				}
+			}
+			return count
+		}
+		sort.SliceStable(group, func(a, b int) bool {
+			return eligible(group[a].ID) < eligible(group[b].ID)
+		})
+
+		for _, team := range group {
+			chosen := pick(team.ID, pool, options.PerTeam, conflicts, result.Load, nil)
+			// Borrow judges from other cases if the group is too small
+			if len(chosen) < options.PerTeam && options.ByCase {
+				chosen = append(chosen, pick(team.ID, all, options.PerTeam-len(chosen), conflicts, result.Load, chosen)...)
+			}
+
+			for _, judge := range chosen {
+				result.Load[judge]++
+				result.Assignments = append(result.Assignments, models.Assignment{
+					Judge:     judge,
+					Team:      team.ID,
+					CreatedAt: now,
+				})
+			}
+			if len(chosen) < options.PerTeam {
+				result.Shortages = append(result.Shortages, Shortage{
+					Team:     team.ID,
+					Assigned: len(chosen),
+					Wanted:   options.PerTeam,
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+// pick returns up to count of the least loaded judges who may grade the team
+func pick(team bson.ObjectID, pool []bson.ObjectID, count int, conflicts ConflictSet, load map[bson.ObjectID]int, taken []bson.ObjectID) []bson.ObjectID {
+	candidates := []bson.ObjectID{}
+	for _, judge := range pool {
+		if conflicts.Has(judge, team) || contains(taken, judge) {
+			continue
+		}
+		candidates = append(candidates, judge)
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		if load[candidates[a]] != load[candidates[b]] {
+			return load[candidates[a]] < load[candidates[b]]
+		}
+		return candidates[a].Hex() < candidates[b].Hex()
+	})
+
+	return candidates[:min(count, len(candidates))]
+}
+
+// caseGroups splits the teams by case and gives every case a share of the judges
+// proportional to its number of teams, but at least perTeam
+func caseGroups(teams []models.Team, judges []bson.ObjectID, perTeam int) ([][]models.Team, [][]bson.ObjectID) {
+	byCase := make(map[bson.ObjectID][]models.Team)
+	cases := []bson.ObjectID{}
+	for _, team := range teams {
+		if _, ok := byCase[team.Case]; !ok {
+			cases = append(cases, team.Case)
+		}
+		byCase[team.Case] = append(byCase[team.Case], team)
+	}
+
+	// Bigger cases choose first
+	sort.SliceStable(cases, func(a, b int) bool {
+		return len(byCase[cases[a]]) > len(byCase[cases[b]])
+	})
+
+	memberships := make(map[bson.ObjectID]int, len(judges))
+	groups := make([][]models.Team, 0, len(cases))
+	pools := make([][]bson.ObjectID, 0, len(cases))
+	for _, caseID := range cases {
+		group := byCase[caseID]
+		size := (len(judges)*len(group) + len(teams) - 1) / len(teams)
+		size = min(max(size, perTeam), len(judges))
+
+		pool := append([]bson.ObjectID{}, judges...)
+		sort.SliceStable(pool, func(a, b int) bool {
+			return memberships[pool[a]] < memberships[pool[b]]
+		})
+		pool = pool[:size]
+		for _, judge := range pool {
+			memberships[judge]++
+		}
+
+		groups = append(groups, group)
+		pools = append(pools, pool)
+	}
+
+	return groups, pools
+}
+
+func contains(ids []bson.ObjectID, id bson.ObjectID) bool {
+	for _, value := range ids {
+		if value == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,185 @@
+package assignment_test
+
+import (
+	"testing"
+
+	"github.com/SomeSuperCoder/global-chat/internal/assignment"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func makeTeams(count int, caseID bson.ObjectID) []models.Team {
+	teams := make([]models.Team, count)
+	for i := range teams {
+		teams[i] = models.Team{ID: bson.NewObjectID(), Case: caseID}
+	}
+	return teams
+}
+
+func makeJudges(count int) []models.User {
+	judges := make([]models.User, count)
+	for i := range judges {
+		judges[i] = models.User{ID: bson.NewObjectID(), Role: models.Judge}
+	}
+	return judges
+}
+
+func TestConflicts(t *testing.T) {
+	team := bson.NewObjectID()
+	other := bson.NewObjectID()
+	judge := models.User{ID: bson.NewObjectID(), Role: models.Judge}
+	teamJudge := models.User{ID: bson.NewObjectID(), Role: models.Judge, Team: team}
+	participant := models.User{ID: bson.NewObjectID(), Role: models.Participant, Team: other}
+	loner := models.User{ID: bson.NewObjectID(), Role: models.Participant}
+
+	set := assignment.Conflicts([]models.Conflict{
+		{Judge: judge.ID, Team: team},
+		{Judge: judge.ID, User: participant.ID},
+		{Judge: teamJudge.ID, User: loner.ID},
+	}, []models.User{judge, teamJudge, participant, loner})
+
+	tests := []struct {
+		name  string
+		judge bson.ObjectID
+		team  bson.ObjectID
+		want  bool
+	}{
+		{"declared team", judge.ID, team, true},
+		{"current team of the participant", judge.ID, other, true},
+		{"own team", teamJudge.ID, team, true},
+		{"participant without a team", teamJudge.ID, other, false},
+		{"no conflict", judge.ID, bson.NewObjectID(), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := set.Has(test.judge, test.team); got != test.want {
+				t.Errorf("Has = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	caseA, caseB := bson.NewObjectID(), bson.NewObjectID()
+	byCase := append(makeTeams(6, caseA), makeTeams(3, caseB)...)
+	teams := makeTeams(7, bson.NilObjectID)
+	judges := makeJudges(4)
+	// Case pools are sized by the share of teams, six judges split evenly over these cases
+	caseJudges := makeJudges(6)
+
+	tests := []struct {
+		name      string
+		teams     []models.Team
+		judges    []models.User
+		conflicts []models.Conflict
+		options   assignment.Options
+		shortages int
+		// Judges borrowed from another case may go over the even load
+		borrowed bool
+	}{
+		{
+			name:    "even split",
+			teams:   makeTeams(6, bson.NilObjectID),
+			judges:  makeJudges(4),
+			options: assignment.Options{PerTeam: 2},
+		},
+		{
+			name:    "uneven split",
+			teams:   makeTeams(5, bson.NilObjectID),
+			judges:  makeJudges(3),
+			options: assignment.Options{PerTeam: 2},
+		},
+		{
+			name:   "conflicts are skipped",
+			teams:  teams,
+			judges: judges,
+			conflicts: []models.Conflict{
+				{Judge: judges[0].ID, Team: teams[0].ID},
+				{Judge: judges[0].ID, Team: teams[1].ID},
+				{Judge: judges[1].ID, Team: teams[0].ID},
+			},
+			options: assignment.Options{PerTeam: 2},
+		},
+		{
+			name:   "teams without enough judges are reported",
+			teams:  teams[:3],
+			judges: judges[:2],
+			conflicts: []models.Conflict{
+				{Judge: judges[0].ID, Team: teams[0].ID},
+			},
+			options:   assignment.Options{PerTeam: 2},
+			shortages: 1,
+		},
+		{
+			name:    "by case",
+			teams:   byCase,
+			judges:  makeJudges(6),
+			options: assignment.Options{PerTeam: 2, ByCase: true},
+		},
+		{
+			name:   "by case with conflicts",
+			teams:  byCase,
+			judges: caseJudges,
+			conflicts: []models.Conflict{
+				{Judge: caseJudges[0].ID, Team: byCase[0].ID},
+				{Judge: caseJudges[1].ID, Team: byCase[1].ID},
+			},
+			options: assignment.Options{PerTeam: 2, ByCase: true},
+		},
+		{
+			name:   "by case borrowing a judge",
+			teams:  byCase,
+			judges: caseJudges,
+			conflicts: []models.Conflict{
+				{Judge: caseJudges[5].ID, Team: byCase[6].ID},
+			},
+			options:  assignment.Options{PerTeam: 2, ByCase: true},
+			borrowed: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conflicts := assignment.Conflicts(test.conflicts, test.judges)
+			result := assignment.Generate(test.teams, test.judges, conflicts, test.options)
+
+			perTeam := make(map[bson.ObjectID]int)
+			seen := make(map[[2]bson.ObjectID]bool)
+			load := make(map[bson.ObjectID]int)
+			for _, a := range result.Assignments {
+				if conflicts.Has(a.Judge, a.Team) {
+					t.Errorf("conflicted pair assigned: judge %s, team %s", a.Judge.Hex(), a.Team.Hex())
+				}
+				pair := [2]bson.ObjectID{a.Judge, a.Team}
+				if seen[pair] {
+					t.Errorf("pair assigned twice: judge %s, team %s", a.Judge.Hex(), a.Team.Hex())
+				}
+				seen[pair] = true
+				perTeam[a.Team]++
+				load[a.Judge]++
+			}
+
+			for _, team := range test.teams {
+				if perTeam[team.ID] > test.options.PerTeam {
+					t.Errorf("team %s got %d judges, want at most %d", team.ID.Hex(), perTeam[team.ID], test.options.PerTeam)
+				}
+			}
+			if len(result.Shortages) != test.shortages {
+				t.Errorf("got %d shortages, want %d", len(result.Shortages), test.shortages)
+			}
+
+			lowest, highest := len(test.teams), 0
+			for _, judge := range test.judges {
+				if result.Load[judge.ID] != load[judge.ID] {
+					t.Errorf("reported load %d, counted %d", result.Load[judge.ID], load[judge.ID])
+				}
+				lowest = min(lowest, load[judge.ID])
+				highest = max(highest, load[judge.ID])
+			}
+			if test.shortages == 0 && !test.borrowed && highest-lowest > 1 {
+				t.Errorf("load ranges from %d to %d, want at most 1 apart", lowest, highest)
+			}
+		})
+	}
+}
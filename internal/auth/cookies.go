@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"time"
+)
+
+const SessionCookie = "session"
+const RefreshCookie = "refresh_token"
+const CSRFCookie = "csrf_token"
+const CSRFHeader = "X-CSRF-Token"
+
+func NewCSRFToken() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(raw), nil
+}
+
+// SetSessionCookies stores a browser session. The CSRF cookie is readable by scripts
+// so the panel can echo it back in the X-CSRF-Token header.
+func SetSessionCookies(w http.ResponseWriter, accessToken string, accessExpiresAt time.Time, refreshToken string, refreshExpiresAt time.Time, csrfToken string) {
+	http.SetCookie(w, newCookie(SessionCookie, accessToken, "/", accessExpiresAt, true))
+	http.SetCookie(w, newCookie(RefreshCookie, refreshToken, "/auth", refreshExpiresAt, true))
+	http.SetCookie(w, newCookie(CSRFCookie, csrfToken, "/", refreshExpiresAt, false))
+}
+
+func ClearSessionCookies(w http.ResponseWriter) {
+	expired := time.Unix(0, 0)
+	http.SetCookie(w, newCookie(SessionCookie, "", "/", expired, true))
+	http.SetCookie(w, newCookie(RefreshCookie, "", "/auth", expired, true))
+	http.SetCookie(w, newCookie(CSRFCookie, "", "/", expired, false))
+}
+
+// CheckCSRF requires state-changing cookie-authenticated requests to echo the session CSRF token
+func CheckCSRF(r *http.Request, claims *Claims) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	header := r.Header.Get(CSRFHeader)
+	return claims.CSRF != "" && header == claims.CSRF
+}
+
+func newCookie(name, value, path string, expires time.Time, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   os.Getenv("COOKIE_DOMAIN"),
+		Expires:  expires,
+		HttpOnly: httpOnly,
+		Secure:   os.Getenv("COOKIE_INSECURE") == "",
+		SameSite: http.SameSiteStrictMode,
+	}
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const ImpersonationTTL = time.Hour
+
+var ErrGrantExpired = errors.New("Impersonation grant expired")
+
+// GrantClaims is the signed payload of the X-Act-As header
+type GrantClaims struct {
+	GrantID   bson.ObjectID `json:"gid"`
+	Admin     bson.ObjectID `json:"admin"`
+	Target    bson.ObjectID `json:"target"`
+	ExpiresAt time.Time     `json:"exp"`
+}
+
+func IssueGrant(claims *GrantClaims) (string, error) {
+	secret, err := Secret()
+	if err != nil {
+		return "", err
+	}
+
+	return Sign(KindImpersonation, claims, secret)
+}
+
+func ParseGrant(token string) (*GrantClaims, error) {
+	secret, err := Secret()
+	if err != nil {
+		return nil, err
+	}
+
+	var claims GrantClaims
+	if err := Verify(KindImpersonation, token, secret, &claims); err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(claims.ExpiresAt) {
+		return nil, ErrGrantExpired
+	}
+
+	return &claims, nil
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// RevocationList remembers when the claims of a user became stale.
+// Access tokens issued before that moment are rejected, so the client has to refresh.
+type RevocationList struct {
+	mutex   sync.RWMutex
+	revoked map[bson.ObjectID]time.Time
+}
+
+var Revocations = NewRevocationList()
+
+func NewRevocationList() *RevocationList {
+	return &RevocationList{
+		revoked: make(map[bson.ObjectID]time.Time),
+	}
+}
+
+func (l *RevocationList) Revoke(userID bson.ObjectID, at time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// Entries older than the access token lifetime can't match anything anymore
+	for id, revokedAt := range l.revoked {
+		if time.Since(revokedAt) > AccessTokenTTL {
+			delete(l.revoked, id)
+		}
+	}
+
+	if current, ok := l.revoked[userID]; !ok || at.After(current) {
+		l.revoked[userID] = at
+	}
+}
+
+func (l *RevocationList) IsRevoked(userID bson.ObjectID, issuedAt time.Time) bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	revokedAt, ok := l.revoked[userID]
+	return ok && issuedAt.Before(revokedAt)
+}
+
+// RevokeUser invalidates every access token of the user issued up to now
+func RevokeUser(ctx context.Context, repo *repository.SessionRepo, userID bson.ObjectID) error {
+	now := time.Now()
+	Revocations.Revoke(userID, now)
+
+	return repo.Revoke(ctx, userID, now)
+}
+
+// CheckRevocation rejects claims issued before the last revocation of the user.
+// The local list only short-circuits revocations made by this instance,
+// the revocations collection is shared by every API instance and is the source of truth.
+func CheckRevocation(ctx context.Context, repo *repository.SessionRepo, claims *Claims) error {
+	if Revocations.IsRevoked(claims.UserID, claims.IssuedAt) {
+		return ErrTokenRevoked
+	}
+
+	revocation, err := repo.GetRevocation(ctx, claims.UserID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	Revocations.Revoke(revocation.User, revocation.RevokedAt)
+	if claims.IssuedAt.Before(revocation.RevokedAt) {
+		return ErrTokenRevoked
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const AccessTokenTTL = 15 * time.Minute
+const RefreshTokenTTL = 30 * 24 * time.Hour
+
+var ErrTokenExpired = errors.New("Token expired")
+var ErrTokenRevoked = errors.New("Token revoked")
+
+type Claims struct {
+	SessionID bson.ObjectID   `json:"sid"`
+	UserID    bson.ObjectID   `json:"sub"`
+	Username  string          `json:"username"`
+	Role      models.UserRole `json:"role"`
+	Team      bson.ObjectID   `json:"team"`
+	IssuedAt  time.Time       `json:"iat"`
+	ExpiresAt time.Time       `json:"exp"`
+	// Only set for cookie based browser sessions
+	CSRF string `json:"csrf,omitempty"`
+}
+
+func NewClaims(sessionID bson.ObjectID, user *models.User) *Claims {
+	now := time.Now()
+	return &Claims{
+		SessionID: sessionID,
+		UserID:    user.ID,
+		Username:  user.Username,
+		Role:      user.Role,
+		Team:      user.Team,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(AccessTokenTTL),
+	}
+}
+
+// User builds the identity carried by the claims without touching the DB
+func (c *Claims) User() *models.User {
+	return &models.User{
+		ID:       c.UserID,
+		Username: c.Username,
+		Role:     c.Role,
+		Team:     c.Team,
+	}
+}
+
+func IssueAccessToken(claims *Claims) (string, error) {
+	secret, err := Secret()
+	if err != nil {
+		return "", err
+	}
+
+	return Sign(KindAccess, claims, secret)
+}
+
+// ParseAccessToken verifies the signature and expiry of an access token.
+// The revocation state is checked separately with CheckRevocation.
+func ParseAccessToken(token string) (*Claims, error) {
+	secret, err := Secret()
+	if err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := Verify(KindAccess, token, secret, &claims); err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(claims.ExpiresAt) {
+		return nil, ErrTokenExpired
+	}
+
+	return &claims, nil
+}
+
+// NewRefreshToken returns a random opaque token and the hash that gets stored in the DB
+func NewRefreshToken() (string, string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+
+	token := hex.EncodeToString(raw)
+	return token, HashRefreshToken(token), nil
+}
+
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const KindTestIdentity = "test-identity"
+const TestIdentityHeader = "X-Test-Identity"
+
+// TestDatabase keeps the fixtures and the test identities away from the real data
+const TestDatabase = "hackathonframework_test"
+
+var ErrTestModeInProduction = errors.New("TEST_AUTH_KEY requires APP_ENV=test")
+var ErrLegacyTestMode = errors.New("API_TEST is no longer supported, use TEST_AUTH_KEY")
+
+// TestIdentity picks the user (and optionally overrides the role) of a test request
+type TestIdentity struct {
+	UserID bson.ObjectID    `json:"user_id"`
+	Role   *models.UserRole `json:"role,omitempty"`
+}
+
+// IsTestEnvironment is the explicit opt-in for test mode.
+// An unset or unknown APP_ENV is treated as production.
+func IsTestEnvironment() bool {
+	return strings.ToLower(os.Getenv("APP_ENV")) == "test"
+}
+
+// CheckTestMode refuses configurations where the test identity provider could leak into production
+func CheckTestMode() error {
+	if os.Getenv("API_TEST") != "" {
+		return ErrLegacyTestMode
+	}
+
+	if os.Getenv("TEST_AUTH_KEY") != "" && !IsTestEnvironment() {
+		return ErrTestModeInProduction
+	}
+
+	return nil
+}
+
+func TestModeEnabled() bool {
+	return os.Getenv("TEST_AUTH_KEY") != "" && IsTestEnvironment()
+}
+
+// DatabaseName picks the test database whenever test mode is enabled
+func DatabaseName() string {
+	if TestModeEnabled() {
+		return TestDatabase
+	}
+
+	return "hackathonframework"
+}
+
+func IssueTestIdentity(identity *TestIdentity) (string, error) {
+	if !TestModeEnabled() {
+		return "", ErrTestModeInProduction
+	}
+
+	return Sign(KindTestIdentity, identity, []byte(os.Getenv("TEST_AUTH_KEY")))
+}
+
+func ParseTestIdentity(token string) (*TestIdentity, error) {
+	if !TestModeEnabled() {
+		return nil, ErrTestModeInProduction
+	}
+
+	var identity TestIdentity
+	if err := Verify(KindTestIdentity, token, []byte(os.Getenv("TEST_AUTH_KEY")), &identity); err != nil {
+		return nil, err
+	}
+
+	return &identity, nil
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrWidgetHashMissing = errors.New("hash is missing")
+var ErrWidgetSignInvalid = errors.New("sign is invalid")
+var ErrWidgetExpired = errors.New("auth_date is expired")
+
+// ValidateWidget checks a Telegram Login Widget payload.
+// Unlike init data, the secret key is the plain SHA-256 of the bot token.
+func ValidateWidget(payload map[string]string, token string, expIn time.Duration) error {
+	hash := payload["hash"]
+	if hash == "" {
+		return ErrWidgetHashMissing
+	}
+
+	// Build the data check string
+	pairs := make([]string, 0, len(payload))
+	for key, value := range payload {
+		if key == "hash" {
+			continue
+		}
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	dataCheckString := strings.Join(pairs, "\n")
+
+	secret := sha256.Sum256([]byte(token))
+	mac := hmac.New(sha256.New, secret[:])
+	mac.Write([]byte(dataCheckString))
+	if !hmac.Equal([]byte(hex.EncodeToString(mac.Sum(nil))), []byte(strings.ToLower(hash))) {
+		return ErrWidgetSignInvalid
+	}
+
+	authDate, err := strconv.ParseInt(payload["auth_date"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid auth_date: %w", err)
+	}
+	if expIn > 0 && time.Since(time.Unix(authDate, 0)) > expIn {
+		return ErrWidgetExpired
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"sync"
 
+	"github.com/SomeSuperCoder/global-chat/internal/auth"
 	statemachine "github.com/SomeSuperCoder/global-chat/internal/bot/state_machine"
 	"github.com/SomeSuperCoder/global-chat/repository"
 	"github.com/SomeSuperCoder/global-chat/utils"
@@ -16,13 +17,22 @@ import (
 )
 
 type Bot struct {
-	client     *mongo.Client
-	database   *mongo.Database
-	Bot        *telego.Bot
-	Handler    *th.BotHandler
-	State      *statemachine.BotState
-	StateMutex *sync.RWMutex
-	UserRepo   *repository.UserRepo
+	client         *mongo.Client
+	database       *mongo.Database
+	Bot            *telego.Bot
+	Handler        *th.BotHandler
+	State          *statemachine.BotState
+	StateMutex     *sync.RWMutex
+	UserRepo       *repository.UserRepo
+	TeamRepo       *repository.TeamRepo
+	ProfileRepo    *repository.ProfileRepo
+	PositionRepo   *repository.PositionRepo
+	CriterionRepo  *repository.CriterionRepo
+	AssignmentRepo *repository.AssignmentRepo
+	CaseRepo       *repository.CaseRepo
+	ScoreRepo      *repository.ScoreRepo
+	SettingsRepo   *repository.SettingsRepo
+	VoteRepo       *repository.VoteRepo
 }
 
 func NewBot() *Bot {
@@ -54,10 +64,19 @@ func (b *Bot) Start(ctx context.Context) {
 	b.client, err = mongo.Connect(options.Client().ApplyURI(connectionString))
 	utils.CheckErrorDeadly(err, "Failed to conneect to MongoDB")
 	defer b.client.Disconnect(ctx)
-	b.database = b.client.Database("hackathonframework")
+	b.database = b.client.Database(auth.DatabaseName())
 
 	// Init database repos
 	b.UserRepo = repository.NewUserRepo(b.database)
+	b.TeamRepo = repository.NewTeamRepo(b.database)
+	b.ProfileRepo = repository.NewProfileRepo(b.database)
+	b.PositionRepo = repository.NewPositionRepo(b.database)
+	b.CriterionRepo = repository.NewCriterionRepo(b.database)
+	b.AssignmentRepo = repository.NewAssignmentRepo(b.database)
+	b.CaseRepo = repository.NewCaseRepo(b.database)
+	b.ScoreRepo = repository.NewScoreRepo(b.database)
+	b.SettingsRepo = repository.NewSettingsRepo(b.database)
+	b.VoteRepo = repository.NewVoteRepo(b.database)
 
 	// Init state manager
 	b.State = statemachine.NewBotState()
@@ -9,9 +9,14 @@ import (
 
 func (b *Bot) registerHandlers() {
 	b.Handler.Handle(b.StartCommand, th.CommandEqual("start"))
+	b.Handler.Handle(b.MatchesCommand, th.CommandEqual("matches"))
+	b.Handler.Handle(b.ProgressCommand, th.CommandEqual("progress"))
+	b.Handler.Handle(b.VoteCommand, th.CommandEqual("vote"))
 
 	// Register callback
 	b.Handler.Handle(b.Register, th.CallbackDataEqual("register"))
+	b.Handler.Handle(b.Nudge, th.CallbackDataPrefix(nudgePrefix))
+	b.Handler.Handle(b.Vote, th.CallbackDataPrefix(votePrefix))
 
 	// Handle STATE_ENTER_NAME
 	b.Handler.Handle(b.EnterName, b.EnterNamePredicate, th.TextMatches(regexp.MustCompile(botregexps.NAME_PATTERN)))
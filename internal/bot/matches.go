@@ -0,0 +1,96 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/SomeSuperCoder/global-chat/internal/matching"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/mymmrac/telego"
+	th "github.com/mymmrac/telego/telegohandler"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func (b *Bot) MatchesCommand(ctx *th.Context, update telego.Update) error {
+	user, ok := b.requireUser(ctx, update)
+	if !ok {
+		return nil
+	}
+
+	board, err := matching.LoadBoard(ctx, b.ProfileRepo, b.PositionRepo, b.TeamRepo, b.UserRepo)
+	if err != nil {
+		b.Bot.SendMessage(ctx, tu.Message(tu.ID(update.Message.Chat.ID), "Ошибка базы данных"))
+		return err
+	}
+
+	teams, err := b.TeamRepo.Find(ctx)
+	if err != nil {
+		b.Bot.SendMessage(ctx, tu.Message(tu.ID(update.Message.Chat.ID), "Ошибка базы данных"))
+		return err
+	}
+	teamNames := make(map[bson.ObjectID]string, len(teams))
+	var ledTeam *models.Team
+	for i, team := range teams {
+		teamNames[team.ID] = team.NameFor(user)
+		if team.Leader == user.ID {
+			ledTeam = &teams[i]
+		}
+	}
+
+	// Team leaders get candidates, solo participants get teams
+	var text string
+	if ledTeam != nil {
+		text = formatCandidates(matching.Top(board.ForTeam(ledTeam.ID), matching.SuggestedMatches), b.userNames(ctx))
+	} else if matches := matching.Top(board.ForUser(user.ID), matching.SuggestedMatches); len(matches) > 0 {
+		text = matching.FormatSuggestions(matches, teamNames)
+	} else {
+		text = "Пока нет подходящих вариантов. Заполните анкету в мини-приложении, чтобы получать предложения"
+	}
+
+	b.Bot.SendMessage(ctx, tu.Message(tu.ID(update.Message.Chat.ID), text))
+	return nil
+}
+
+// requireUser loads the account of the sender or asks them to register
+func (b *Bot) requireUser(ctx *th.Context, update telego.Update) (*models.User, bool) {
+	user, err := b.UserRepo.GetByUsername(ctx, update.Message.From.Username)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		b.Bot.SendMessage(ctx, tu.Message(tu.ID(update.Message.Chat.ID), "Вы пока что не зарегестированы на хакатон. Нажмите /start"))
+		return nil, false
+	} else if err != nil {
+		b.Bot.SendMessage(ctx, tu.Message(tu.ID(update.Message.Chat.ID), "Ошибка базы данных"))
+		return nil, false
+	}
+
+	return user, true
+}
+
+func (b *Bot) userNames(ctx *th.Context) map[bson.ObjectID]string {
+	names := make(map[bson.ObjectID]string)
+	users, _ := b.UserRepo.Find(ctx)
+	for _, user := range users {
+		names[user.ID] = fmt.Sprintf("%s (@%s)", user.Name, user.Username)
+	}
+
+	return names
+}
+
+func formatCandidates(matches []matching.Match, names map[bson.ObjectID]string) string {
+	if len(matches) == 0 {
+		return "Пока нет подходящих участников. Опубликуйте открытые позиции в мини-приложении"
+	}
+
+	var builder strings.Builder
+	builder.WriteString("Участники, которые могут подойти вашей команде:\n")
+	for _, match := range matches {
+		builder.WriteString(fmt.Sprintf("\n• %s на позицию %s", names[match.Profile.User], match.Position.Role))
+		if len(match.SharedSkills) > 0 {
+			builder.WriteString(fmt.Sprintf(" (общие навыки: %s)", strings.Join(match.SharedSkills, ", ")))
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,106 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/SomeSuperCoder/global-chat/internal/progress"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/mymmrac/telego"
+	th "github.com/mymmrac/telego/telegohandler"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const nudgePrefix = "nudge:"
+
+// ProgressCommand shows admins how far the judges are, with a reminder button for every lagging judge
+func (b *Bot) ProgressCommand(ctx *th.Context, update telego.Update) error {
+	user, ok := b.requireUser(ctx, update)
+	if !ok {
+		return nil
+	}
+	if user.Role != models.Admin {
+		b.Bot.SendMessage(ctx, tu.Message(tu.ID(update.Message.Chat.ID), "Команда доступна только администраторам"))
+		return nil
+	}
+
+	judges, err := b.loadProgress(ctx)
+	if err != nil {
+		b.Bot.SendMessage(ctx, tu.Message(tu.ID(update.Message.Chat.ID), "Ошибка базы данных"))
+		return err
+	}
+
+	rows := [][]telego.InlineKeyboardButton{}
+	for _, judge := range judges {
+		if !judge.Done() && judge.ChatID != 0 {
+			rows = append(rows, tu.InlineKeyboardRow(
+				tu.InlineKeyboardButton("Напомнить: "+judge.Name).WithCallbackData(nudgePrefix+judge.Judge.Hex()),
+			))
+		}
+	}
+
+	message := tu.Message(tu.ID(update.Message.Chat.ID), progress.Summary(judges))
+	if len(rows) > 0 {
+		message = message.WithReplyMarkup(tu.InlineKeyboard(rows...))
+	}
+	b.Bot.SendMessage(ctx, message)
+	return nil
+}
+
+// Nudge sends the reminder chosen under the /progress message
+func (b *Bot) Nudge(ctx *th.Context, update telego.Update) error {
+	query := update.CallbackQuery
+	answer := func(text string) {
+		b.Bot.AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText(text))
+	}
+
+	user, err := b.UserRepo.GetByUsername(ctx, query.From.Username)
+	if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && user.Role != models.Admin) {
+		answer("Команда доступна только администраторам")
+		return nil
+	} else if err != nil {
+		answer("Ошибка базы данных")
+		return err
+	}
+
+	judgeID, err := bson.ObjectIDFromHex(strings.TrimPrefix(query.Data, nudgePrefix))
+	if err != nil {
+		answer("Неизвестный судья")
+		return nil
+	}
+
+	judges, err := b.loadProgress(ctx)
+	if err != nil {
+		answer("Ошибка базы данных")
+		return err
+	}
+
+	for i := range judges {
+		judge := &judges[i]
+		if judge.Judge != judgeID {
+			continue
+		}
+		if judge.Done() {
+			answer("Судья уже выставил все оценки")
+			return nil
+		}
+
+		_, err := b.Bot.SendMessage(ctx, tu.Message(tu.ID(judge.ChatID), progress.Reminder(judge)))
+		if err != nil {
+			answer("Не удалось отправить напоминание")
+			return err
+		}
+		answer("Напоминание отправлено")
+		return nil
+	}
+
+	answer("Неизвестный судья")
+	return nil
+}
+
+// loadProgress counts the scores given outside of rounds
+func (b *Bot) loadProgress(ctx *th.Context) ([]progress.JudgeProgress, error) {
+	return progress.Load(ctx, b.UserRepo, b.TeamRepo, b.CriterionRepo, b.AssignmentRepo, b.CaseRepo, b.ScoreRepo, nil)
+}
@@ -0,0 +1,115 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/SomeSuperCoder/global-chat/internal/voting"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"github.com/mymmrac/telego"
+	th "github.com/mymmrac/telego/telegohandler"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const votePrefix = "vote:"
+
+// VoteCommand offers the teams the user can still vote for as an inline keyboard
+func (b *Bot) VoteCommand(ctx *th.Context, update telego.Update) error {
+	user, ok := b.requireUser(ctx, update)
+	if !ok {
+		return nil
+	}
+
+	ballot, err := voting.LoadBallot(ctx, b.SettingsRepo, b.TeamRepo, b.VoteRepo, user)
+	if err != nil {
+		b.Bot.SendMessage(ctx, tu.Message(tu.ID(update.Message.Chat.ID), "Ошибка базы данных"))
+		return err
+	}
+
+	var text string
+	rows := [][]telego.InlineKeyboardButton{}
+	switch {
+	case user.Role != models.Participant:
+		text = "Голосовать могут только участники"
+	case !ballot.Open:
+		text = "Голосование за приз зрительских симпатий сейчас закрыто"
+	case ballot.Left == 0:
+		text = "Вы уже использовали все свои голоса. Спасибо за участие!"
+	default:
+		for _, team := range ballot.Teams {
+			if team.Votable() {
+				rows = append(rows, tu.InlineKeyboardRow(
+					tu.InlineKeyboardButton(team.Name).WithCallbackData(votePrefix+team.Team.Hex()),
+				))
+			}
+		}
+		if len(rows) == 0 {
+			text = "Нет команд, за которые вы можете проголосовать"
+		} else {
+			text = fmt.Sprintf("Приз зрительских симпатий: выберите команду. Осталось голосов: %d", ballot.Left)
+		}
+	}
+
+	message := tu.Message(tu.ID(update.Message.Chat.ID), text)
+	if len(rows) > 0 {
+		message = message.WithReplyMarkup(tu.InlineKeyboard(rows...))
+	}
+	b.Bot.SendMessage(ctx, message)
+	return nil
+}
+
+// Vote casts the vote chosen under the /vote message
+func (b *Bot) Vote(ctx *th.Context, update telego.Update) error {
+	query := update.CallbackQuery
+	answer := func(text string) {
+		b.Bot.AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText(text))
+	}
+
+	user, err := b.UserRepo.GetByUsername(ctx, query.From.Username)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		answer("Вы пока что не зарегестированы на хакатон. Нажмите /start")
+		return nil
+	} else if err != nil {
+		answer("Ошибка базы данных")
+		return err
+	}
+
+	teamID, err := bson.ObjectIDFromHex(strings.TrimPrefix(query.Data, votePrefix))
+	if err != nil {
+		answer("Команда не найдена")
+		return nil
+	}
+
+	_, team, err := voting.Cast(ctx, b.SettingsRepo, b.TeamRepo, b.VoteRepo, user, teamID)
+	switch {
+	case errors.Is(err, voting.ErrClosed):
+		answer("Голосование закрыто")
+		return nil
+	case errors.Is(err, voting.ErrOwnTeam):
+		answer("Нельзя голосовать за свою команду")
+		return nil
+	case errors.Is(err, voting.ErrNotParticipant):
+		answer("Голосовать могут только участники")
+		return nil
+	case errors.Is(err, repository.ErrAlreadyVoted):
+		answer("Вы уже голосовали за эту команду")
+		return nil
+	case errors.Is(err, repository.ErrNoVotesLeft):
+		answer("Вы уже использовали все свои голоса")
+		return nil
+	case errors.Is(err, mongo.ErrNoDocuments):
+		answer("Команда не найдена")
+		return nil
+	case err != nil:
+		answer("Ошибка базы данных")
+		return err
+	}
+
+	answer("Голос принят")
+	b.Bot.SendMessage(ctx, tu.Message(tu.ID(query.From.ID), fmt.Sprintf("Ваш голос за команду «%s» принят", team.NameFor(user))))
+	return nil
+}
@@ -0,0 +1,172 @@
+package feedback
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Report is the written feedback of the judges for a team, grouped by criterion
+type Report struct {
+	Team     bson.ObjectID     `json:"team"`
+	TeamName string            `json:"team_name"`
+	Criteria []CriterionReport `json:"criteria"`
+	Overall  []Comment         `json:"overall"`
+}
+
+type CriterionReport struct {
+	Criterion bson.ObjectID `json:"criterion"`
+	Name      string        `json:"name"`
+	MaxScore  int           `json:"max_score"`
+	Comments  []Comment     `json:"comments"`
+}
+
+// Comment is written by the judge numbered Judge within the report, the name is only known if the judge signed
+type Comment struct {
+	Judge     int    `json:"judge"`
+	JudgeName string `json:"judge_name,omitempty"`
+	Value     *int   `json:"value,omitempty"`
+	Text      string `json:"text"`
+}
+
+type Input struct {
+	Team     models.Team
+	Criteria []models.Criterion
+	Scores   []models.Score
+	Feedback []models.Feedback
+	// Names of the judges, shown for signed feedback or to admins when Reveal is set
+	Judges map[bson.ObjectID]string
+	Reveal bool
+}
+
+// Build collects the comments of the team, scores without a comment are left out
+func Build(in Input) Report {
+	report := Report{
+		Team:     in.Team.ID,
+		TeamName: in.Team.Name,
+		Criteria: []CriterionReport{},
+		Overall:  []Comment{},
+	}
+
+	signed := make(map[bson.ObjectID]bool)
+	authors := []bson.ObjectID{}
+	seen := make(map[bson.ObjectID]bool)
+	addAuthor := func(judge bson.ObjectID) {
+		if !seen[judge] {
+			seen[judge] = true
+			authors = append(authors, judge)
+		}
+	}
+	for _, feedback := range in.Feedback {
+		if feedback.Team != in.Team.ID || strings.TrimSpace(feedback.Text) == "" {
+			continue
+		}
+		signed[feedback.Judge] = feedback.Signed
+		addAuthor(feedback.Judge)
+	}
+	for _, score := range in.Scores {
+		if score.Team == in.Team.ID && strings.TrimSpace(score.Comment) != "" {
+			addAuthor(score.Judge)
+		}
+	}
+
+	// Judges are numbered in a stable order so the same judge has the same number everywhere
+	sort.Slice(authors, func(i, j int) bool {
+		return authors[i].Hex() < authors[j].Hex()
+	})
+	numbers := make(map[bson.ObjectID]int, len(authors))
+	for i, judge := range authors {
+		numbers[judge] = i + 1
+	}
+	comment := func(judge bson.ObjectID, value *int, text string) Comment {
+		c := Comment{
+			Judge: numbers[judge],
+			Value: value,
+			Text:  strings.TrimSpace(text),
+		}
+		if in.Reveal || signed[judge] {
+			c.JudgeName = in.Judges[judge]
+		}
+		return c
+	}
+
+	byCriterion := make(map[bson.ObjectID][]Comment)
+	for _, score := range in.Scores {
+		if score.Team != in.Team.ID || strings.TrimSpace(score.Comment) == "" {
+			continue
+		}
+		value := score.Value
+		byCriterion[score.Criterion] = append(byCriterion[score.Criterion], comment(score.Judge, &value, score.Comment))
+	}
+	for _, criterion := range in.Criteria {
+		comments := byCriterion[criterion.ID]
+		if len(comments) == 0 {
+			continue
+		}
+		sortComments(comments)
+
+		_, maxScore := criterion.ScoreRange()
+		report.Criteria = append(report.Criteria, CriterionReport{
+			Criterion: criterion.ID,
+			Name:      criterion.Text,
+			MaxScore:  maxScore,
+			Comments:  comments,
+		})
+	}
+
+	for _, feedback := range in.Feedback {
+		if feedback.Team == in.Team.ID && strings.TrimSpace(feedback.Text) != "" {
+			report.Overall = append(report.Overall, comment(feedback.Judge, nil, feedback.Text))
+		}
+	}
+	sortComments(report.Overall)
+
+	return report
+}
+
+func (r Report) IsEmpty() bool {
+	return len(r.Criteria) == 0 && len(r.Overall) == 0
+}
+
+// Text formats the report for the bot and the downloadable file
+func (r Report) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Отзывы жюри для команды «%s»\n", r.TeamName)
+	if r.IsEmpty() {
+		b.WriteString("\nСудьи пока не оставили отзывов\n")
+		return b.String()
+	}
+
+	for _, criterion := range r.Criteria {
+		fmt.Fprintf(&b, "\n%s (макс. %d)\n", criterion.Name, criterion.MaxScore)
+		for _, c := range criterion.Comments {
+			fmt.Fprintf(&b, "• %s, оценка %d: %s\n", c.author(), *c.Value, c.Text)
+		}
+	}
+
+	if len(r.Overall) > 0 {
+		b.WriteString("\nОбщий отзыв\n")
+		for _, c := range r.Overall {
+			fmt.Fprintf(&b, "• %s: %s\n", c.author(), c.Text)
+		}
+	}
+
+	return b.String()
+}
+
+func (c Comment) author() string {
+	if c.JudgeName != "" {
+		return c.JudgeName
+	}
+
+	return fmt.Sprintf("Судья %d", c.Judge)
+}
+
+func sortComments(comments []Comment) {
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].Judge < comments[j].Judge
+	})
+}
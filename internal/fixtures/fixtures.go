@@ -0,0 +1,83 @@
+package fixtures
+
+import (
+	"context"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/internal"
+	"github.com/SomeSuperCoder/global-chat/internal/moderation"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Fixed IDs so tests can address the seeded documents directly
+var (
+	AdminID, _       = bson.ObjectIDFromHex("00000000000000000000a001")
+	JudgeID, _       = bson.ObjectIDFromHex("00000000000000000000a002")
+	LeaderID, _      = bson.ObjectIDFromHex("00000000000000000000a003")
+	MemberID, _      = bson.ObjectIDFromHex("00000000000000000000a004")
+	ParticipantID, _ = bson.ObjectIDFromHex("00000000000000000000a005")
+
+	TeamID, _      = bson.ObjectIDFromHex("00000000000000000000b001")
+	CaseID, _      = bson.ObjectIDFromHex("00000000000000000000c001")
+	CriterionID, _ = bson.ObjectIDFromHex("00000000000000000000d001")
+)
+
+func Users() []models.User {
+	birthdate := time.Date(2005, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	return []models.User{
+		{ID: AdminID, Name: "Админов Админ", Birthdate: birthdate, Role: models.Admin, Team: internal.UndefinedObjectID, Username: "test_admin"},
+		{ID: JudgeID, Name: "Судьев Судья", Birthdate: birthdate, Role: models.Judge, Team: internal.UndefinedObjectID, Username: "test_judge"},
+		{ID: LeaderID, Name: "Лидеров Лидер", Birthdate: birthdate, Role: models.Participant, Team: TeamID, Username: "test_leader"},
+		{ID: MemberID, Name: "Участников Участник", Birthdate: birthdate, Role: models.Participant, Team: TeamID, Username: "test_member"},
+		{ID: ParticipantID, Name: "Одиночкин Одиночка", Birthdate: birthdate, Role: models.Participant, Team: internal.UndefinedObjectID, Username: "test_participant"},
+	}
+}
+
+func Teams() []models.Team {
+	return []models.Team{
+		{ID: TeamID, Name: "Test Team", NameKey: moderation.NameKey("Test Team"), NameStatus: models.NameApproved, Leader: LeaderID, Repos: []string{}},
+	}
+}
+
+func Cases() []models.Case {
+	return []models.Case{
+		{ID: CaseID, Name: "Test Case", Description: "Seeded case"},
+	}
+}
+
+func Criteria() []models.Criterion {
+	return []models.Criterion{
+		{ID: CriterionID, Text: "Test Criterion", MaxScore: models.DefaultMaxScore, Weight: 1},
+	}
+}
+
+// Seed replaces the fixture documents, leaving everything else untouched
+func Seed(ctx context.Context, db *mongo.Database) error {
+	if err := seed(ctx, db.Collection("users"), Users(), func(v models.User) bson.ObjectID { return v.ID }); err != nil {
+		return err
+	}
+	if err := seed(ctx, db.Collection("teams"), Teams(), func(v models.Team) bson.ObjectID { return v.ID }); err != nil {
+		return err
+	}
+	if err := seed(ctx, db.Collection("cases"), Cases(), func(v models.Case) bson.ObjectID { return v.ID }); err != nil {
+		return err
+	}
+	return seed(ctx, db.Collection("criteria"), Criteria(), func(v models.Criterion) bson.ObjectID { return v.ID })
+}
+
+func seed[T any](ctx context.Context, c *mongo.Collection, values []T, id func(T) bson.ObjectID) error {
+	for _, value := range values {
+		_, err := c.ReplaceOne(ctx, bson.M{
+			"_id": id(value),
+		}, value, options.Replace().SetUpsert(true))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package matching
+
+import (
+	"context"
+
+	"github.com/SomeSuperCoder/global-chat/internal"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Board is the current state of the marketplace
+type Board struct {
+	Profiles  []models.SeekerProfile
+	Positions []models.OpenPosition
+	TeamCases map[bson.ObjectID]bson.ObjectID
+}
+
+// LoadBoard skips profiles of users who already joined a team and positions of deleted teams
+func LoadBoard(ctx context.Context, profileRepo *repository.ProfileRepo, positionRepo *repository.PositionRepo, teamRepo *repository.TeamRepo, userRepo *repository.UserRepo) (*Board, error) {
+	profiles, err := profileRepo.Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := positionRepo.Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := teamRepo.Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users, err := userRepo.Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &Board{
+		Profiles:  []models.SeekerProfile{},
+		Positions: []models.OpenPosition{},
+		TeamCases: make(map[bson.ObjectID]bson.ObjectID, len(teams)),
+	}
+	for _, team := range teams {
+		board.TeamCases[team.ID] = team.Case
+	}
+
+	solo := make(map[bson.ObjectID]bool, len(users))
+	for _, user := range users {
+		solo[user.ID] = user.Team.IsZero() || user.Team == internal.UndefinedObjectID
+	}
+
+	for _, profile := range profiles {
+		if solo[profile.User] {
+			board.Profiles = append(board.Profiles, profile)
+		}
+	}
+	for _, position := range positions {
+		if _, ok := board.TeamCases[position.Team]; ok {
+			board.Positions = append(board.Positions, position)
+		}
+	}
+
+	return board, nil
+}
+
+// ForUser ranks the open positions for the profile of the user
+func (b *Board) ForUser(userID bson.ObjectID) []Match {
+	for i := range b.Profiles {
+		if b.Profiles[i].User == userID {
+			return RankPositions(&b.Profiles[i], b.Positions, b.TeamCases)
+		}
+	}
+
+	return []Match{}
+}
+
+// ForTeam ranks the profiles against every open position of the team
+func (b *Board) ForTeam(teamID bson.ObjectID) []Match {
+	matches := []Match{}
+	for i := range b.Positions {
+		if b.Positions[i].Team == teamID {
+			matches = append(matches, RankProfiles(&b.Positions[i], b.Profiles, b.TeamCases[teamID])...)
+		}
+	}
+
+	sortMatches(matches)
+	return matches
+}
@@ -0,0 +1,131 @@
+package matching
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const roleBonus = 0.25
+const caseBonus = 0.25
+
+// SuggestedMatches is how many matches are suggested at once in the API and the bot
+const SuggestedMatches = 3
+
+type Match struct {
+	Profile      models.SeekerProfile `json:"profile"`
+	Position     models.OpenPosition  `json:"position"`
+	Score        float64              `json:"score"`
+	SharedSkills []string             `json:"shared_skills"`
+}
+
+// Score ranks a pair by the Jaccard overlap of skills,
+// with a bonus for the same role and for the preferred case of the team
+func Score(profile *models.SeekerProfile, position *models.OpenPosition, teamCase bson.ObjectID) Match {
+	profileSkills := NormalizeSkills(profile.Skills)
+	positionSkills := NormalizeSkills(position.Skills)
+
+	shared := []string{}
+	for _, skill := range profileSkills {
+		if slices.Contains(positionSkills, skill) {
+			shared = append(shared, skill)
+		}
+	}
+
+	var score float64
+	if union := len(profileSkills) + len(positionSkills) - len(shared); union > 0 {
+		score = float64(len(shared)) / float64(union)
+	}
+
+	if profile.Role != "" && strings.EqualFold(strings.TrimSpace(profile.Role), strings.TrimSpace(position.Role)) {
+		score += roleBonus
+	}
+	if !profile.PreferredCase.IsZero() && profile.PreferredCase == teamCase {
+		score += caseBonus
+	}
+
+	return Match{
+		Profile:      *profile,
+		Position:     *position,
+		Score:        score,
+		SharedSkills: shared,
+	}
+}
+
+// RankPositions returns the positions best suited for the profile first
+func RankPositions(profile *models.SeekerProfile, positions []models.OpenPosition, teamCases map[bson.ObjectID]bson.ObjectID) []Match {
+	matches := make([]Match, 0, len(positions))
+	for i := range positions {
+		matches = append(matches, Score(profile, &positions[i], teamCases[positions[i].Team]))
+	}
+
+	sortMatches(matches)
+	return matches
+}
+
+// RankProfiles returns the profiles best suited for the position first
+func RankProfiles(position *models.OpenPosition, profiles []models.SeekerProfile, teamCase bson.ObjectID) []Match {
+	matches := make([]Match, 0, len(profiles))
+	for i := range profiles {
+		matches = append(matches, Score(&profiles[i], position, teamCase))
+	}
+
+	sortMatches(matches)
+	return matches
+}
+
+func NormalizeSkills(skills []string) []string {
+	normalized := []string{}
+	for _, skill := range skills {
+		skill = strings.ToLower(strings.TrimSpace(skill))
+		if skill != "" && !slices.Contains(normalized, skill) {
+			normalized = append(normalized, skill)
+		}
+	}
+
+	return normalized
+}
+
+// Top drops matches without anything in common and keeps at most limit entries
+func Top(matches []Match, limit int) []Match {
+	top := []Match{}
+	for _, match := range matches {
+		if match.Score <= 0 || len(top) == limit {
+			break
+		}
+		top = append(top, match)
+	}
+
+	return top
+}
+
+func sortMatches(matches []Match) {
+	// Stable ordering: best score, then the older profile/position
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		if matches[i].Position.ID != matches[j].Position.ID {
+			return matches[i].Position.ID.Hex() < matches[j].Position.ID.Hex()
+		}
+		return matches[i].Profile.ID.Hex() < matches[j].Profile.ID.Hex()
+	})
+}
+
+// FormatSuggestions renders matches as a bot message
+func FormatSuggestions(matches []Match, teamNames map[bson.ObjectID]string) string {
+	var builder strings.Builder
+	builder.WriteString("Команды, которым вы можете подойти:\n")
+	for _, match := range matches {
+		builder.WriteString(fmt.Sprintf("\n• «%s» ищет: %s", teamNames[match.Position.Team], match.Position.Role))
+		if len(match.SharedSkills) > 0 {
+			builder.WriteString(fmt.Sprintf(" (общие навыки: %s)", strings.Join(match.SharedSkills, ", ")))
+		}
+	}
+
+	return builder.String()
+}
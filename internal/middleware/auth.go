@@ -2,13 +2,14 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
-	"time"
+	"strings"
 
-	"github.com/SomeSuperCoder/global-chat/internal"
+	"github.com/SomeSuperCoder/global-chat/internal/auth"
 	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
 	"github.com/SomeSuperCoder/global-chat/utils"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
@@ -24,34 +25,136 @@ func ExtractUserAuth(r *http.Request) *models.User {
 	return userAuth
 }
 
+// ExtractOptionalUserAuth returns nil for anonymous requests
+func ExtractOptionalUserAuth(r *http.Request) *models.User {
+	userAuth, _ := r.Context().Value(UserKey).(*models.User)
+	return userAuth
+}
+
 func AuthMiddleware(next http.HandlerFunc, db *mongo.Database) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var user *models.User
-		if os.Getenv("API_TEST") == "" {
-			var err error
-			var code int
-			user, err, code = utils.Authorize(r, db)
-			if err != nil {
-				http.Error(w, fmt.Errorf("Failed to authorize: %w", err).Error(), code)
-				return
-			}
-		} else {
-			user = &models.User{
-				ID:        internal.UndefinedObjectID,
-				Username:  "test",
-				Name:      "Mr. Test",
-				Birthdate: time.Now(),
-				Role:      models.Admin,
-				ChatID:    0,
-				Team:      internal.UndefinedObjectID,
-			}
+		user, err, code := authenticate(r, db)
+		if err != nil {
+			http.Error(w, fmt.Errorf("Failed to authorize: %w", err).Error(), code)
+			return
+		}
+
+		serveAs(w, r, next, db, user)
+	}
+}
+
+// OptionalAuthMiddleware identifies the caller when credentials are present
+// and lets anonymous requests through otherwise
+func OptionalAuthMiddleware(next http.HandlerFunc, db *mongo.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !hasCredentials(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		user, err, _ := authenticate(r, db)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		serveAs(w, r, next, db, user)
+	}
+}
+
+func serveAs(w http.ResponseWriter, r *http.Request, next http.HandlerFunc, db *mongo.Database, user *models.User) {
+	if token := r.Header.Get(ActAsHeader); token != "" {
+		target, claims, err, code := impersonate(r, db, user, token)
+		if err != nil {
+			http.Error(w, fmt.Errorf("Failed to impersonate: %w", err).Error(), code)
+			return
+		}
+
+		serveImpersonated(w, r, next, db, user, target, claims)
+		return
+	}
+
+	ctx := r.Context()
+	ctx = context.WithValue(ctx, UserKey, user)
+
+	r = r.WithContext(ctx)
+
+	next.ServeHTTP(w, r)
+}
+
+func hasCredentials(r *http.Request) bool {
+	if r.Header.Get("Authorization") != "" || r.Header.Get("TG-Init-Data") != "" || r.Header.Get(auth.TestIdentityHeader) != "" {
+		return true
+	}
+
+	cookie, err := r.Cookie(auth.SessionCookie)
+	return err == nil && cookie.Value != ""
+}
+
+// authenticate prefers a session access token and falls back to Telegram init data
+func authenticate(r *http.Request, db *mongo.Database) (*models.User, error, int) {
+	if token := r.Header.Get(auth.TestIdentityHeader); token != "" && auth.TestModeEnabled() {
+		return authenticateTestIdentity(r, db, token)
+	}
+
+	if token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); found {
+		claims, err, status := parseAccessToken(r, db, token)
+		if err != nil {
+			return nil, err, status
 		}
 
-		ctx := r.Context()
-		ctx = context.WithValue(ctx, UserKey, user)
+		return claims.User(), nil, http.StatusNoContent
+	}
 
-		r = r.WithContext(ctx)
+	// Browser sessions from the Login Widget
+	if cookie, err := r.Cookie(auth.SessionCookie); err == nil && cookie.Value != "" {
+		claims, err, status := parseAccessToken(r, db, cookie.Value)
+		if err != nil {
+			return nil, err, status
+		}
+
+		if !auth.CheckCSRF(r, claims) {
+			return nil, errors.New("CSRF token mismatch"), http.StatusForbidden
+		}
+
+		return claims.User(), nil, http.StatusNoContent
+	}
+
+	return utils.Authorize(r, repository.NewUserRepo(db))
+}
 
-		next.ServeHTTP(w, r)
+// parseAccessToken verifies the token and checks the shared revocations
+func parseAccessToken(r *http.Request, db *mongo.Database, token string) (*auth.Claims, error, int) {
+	claims, err := auth.ParseAccessToken(token)
+	if err != nil {
+		return nil, err, http.StatusUnauthorized
 	}
+
+	err = auth.CheckRevocation(r.Context(), repository.NewSessionRepo(db), claims)
+	if errors.Is(err, auth.ErrTokenRevoked) {
+		return nil, err, http.StatusUnauthorized
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to check session revocation: %w", err), http.StatusInternalServerError
+	}
+
+	return claims, nil, http.StatusNoContent
+}
+
+func authenticateTestIdentity(r *http.Request, db *mongo.Database, token string) (*models.User, error, int) {
+	identity, err := auth.ParseTestIdentity(token)
+	if err != nil {
+		return nil, err, http.StatusUnauthorized
+	}
+
+	user, err := repository.NewUserRepo(db).GetByID(r.Context(), identity.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("Test user not found: %w", err), http.StatusUnauthorized
+	}
+
+	if identity.Role != nil {
+		user.Role = *identity.Role
+	}
+
+	return user, nil, http.StatusNoContent
 }
@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/SomeSuperCoder/global-chat/internal/auth"
+	"github.com/sirupsen/logrus"
+)
+
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           int
+}
+
+// CORSConfigFromEnv reads the CORS_* variables. No origin is allowed unless configured.
+func CORSConfigFromEnv() *CORSConfig {
+	maxAge, err := strconv.Atoi(envOr("CORS_MAX_AGE", "600"))
+	if err != nil {
+		maxAge = 600
+	}
+
+	config := &CORSConfig{
+		AllowedOrigins:   splitList(envOr("CORS_ALLOWED_ORIGINS", "")),
+		AllowedMethods:   splitList(envOr("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE")),
+		AllowedHeaders:   splitList(envOr("CORS_ALLOWED_HEADERS", "Content-Type,Authorization,TG-Init-Data,X-CSRF-Token,X-Act-As")),
+		ExposedHeaders:   splitList(envOr("CORS_EXPOSED_HEADERS", "X-Impersonated-By,X-Impersonated-User")),
+		AllowCredentials: envOr("CORS_ALLOW_CREDENTIALS", "true") == "true",
+		MaxAge:           maxAge,
+	}
+
+	// The test identity header only exists in test mode
+	if auth.TestModeEnabled() && !slices.Contains(config.AllowedHeaders, auth.TestIdentityHeader) {
+		config.AllowedHeaders = append(config.AllowedHeaders, auth.TestIdentityHeader)
+	}
+
+	// Reflecting any origin with credentials would let every site act as the logged in user
+	if config.AllowCredentials && slices.Contains(config.AllowedOrigins, "*") {
+		logrus.Warn("CORS_ALLOWED_ORIGINS=* is ignored while CORS_ALLOW_CREDENTIALS is enabled, list the origins explicitly")
+		config.AllowedOrigins = slices.DeleteFunc(config.AllowedOrigins, func(origin string) bool {
+			return origin == "*"
+		})
+	}
+
+	return config
+}
+
+func (c *CORSConfig) isOriginAllowed(origin string) bool {
+	return slices.Contains(c.AllowedOrigins, "*") || slices.Contains(c.AllowedOrigins, origin)
+}
+
+func CORSMiddleware(config *CORSConfig, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		allowedOrigin := origin != "" && config.isOriginAllowed(origin)
+
+		w.Header().Add("Vary", "Origin")
+		if allowedOrigin {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if config.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		if r.Method != http.MethodOptions {
+			if allowedOrigin && len(config.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(config.ExposedHeaders, ", "))
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// The method based patterns don't match OPTIONS, so ask the mux what the path supports
+		allowed, found := allowedMethods(next, r)
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Allow", strings.Join(append(allowed, http.MethodOptions), ", "))
+
+		requestedMethod := r.Header.Get("Access-Control-Request-Method")
+		if requestedMethod == "" || !allowedOrigin {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if !slices.Contains(allowed, requestedMethod) || !slices.Contains(config.AllowedMethods, requestedMethod) {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(intersect(allowed, config.AllowedMethods), ", "))
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(config.AllowedHeaders, ", "))
+		w.Header().Set("Access-Control-Max-Age", strconv.Itoa(config.MaxAge))
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// allowedMethods probes the mux with an OPTIONS request.
+// Routes with a method answer 405 with an Allow header, unknown paths answer 404.
+// Only method-less patterns (like /health) are actually served, so they must be side effect free.
+func allowedMethods(mux http.Handler, r *http.Request) ([]string, bool) {
+	probe := &probeWriter{header: make(http.Header), statusCode: http.StatusOK}
+	mux.ServeHTTP(probe, r.Clone(r.Context()))
+
+	switch probe.statusCode {
+	case http.StatusNotFound:
+		return nil, false
+	case http.StatusMethodNotAllowed:
+		return splitList(probe.header.Get("Allow")), true
+	}
+
+	// A method-less pattern accepts everything
+	return []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}, true
+}
+
+type probeWriter struct {
+	header     http.Header
+	statusCode int
+	written    bool
+}
+
+func (p *probeWriter) Header() http.Header {
+	return p.header
+}
+
+func (p *probeWriter) Write(b []byte) (int, error) {
+	p.written = true
+	return len(b), nil
+}
+
+func (p *probeWriter) WriteHeader(code int) {
+	if !p.written {
+		p.statusCode = code
+		p.written = true
+	}
+}
+
+// ====================
+func envOr(key string, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
+
+func splitList(value string) []string {
+	var values []string
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+func intersect(a []string, b []string) []string {
+	var values []string
+	for _, item := range a {
+		if slices.Contains(b, item) {
+			values = append(values, item)
+		}
+	}
+	return values
+}
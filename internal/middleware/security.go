@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// SecurityHeadersConfig maps header names to values. Empty values are not sent.
+type SecurityHeadersConfig map[string]string
+
+// SecurityHeadersConfigFromEnv starts from defaults suitable for a JSON API.
+// Setting a SECURITY_* variable to an empty string disables the header.
+func SecurityHeadersConfigFromEnv() SecurityHeadersConfig {
+	config := SecurityHeadersConfig{
+		"X-Content-Type-Options":  envOr("SECURITY_CONTENT_TYPE_OPTIONS", "nosniff"),
+		"X-Frame-Options":         envOr("SECURITY_FRAME_OPTIONS", "DENY"),
+		"Referrer-Policy":         envOr("SECURITY_REFERRER_POLICY", "no-referrer"),
+		"Content-Security-Policy": envOr("SECURITY_CONTENT_SECURITY_POLICY", "default-src 'none'; frame-ancestors 'none'"),
+		"Permissions-Policy":      envOr("SECURITY_PERMISSIONS_POLICY", "camera=(), microphone=(), geolocation=()"),
+		"Cache-Control":           envOr("SECURITY_CACHE_CONTROL", "no-store"),
+	}
+
+	// HSTS only makes sense behind TLS, so it is opt-in
+	if maxAge, err := strconv.Atoi(os.Getenv("SECURITY_HSTS_MAX_AGE")); err == nil && maxAge > 0 {
+		config["Strict-Transport-Security"] = "max-age=" + strconv.Itoa(maxAge) + "; includeSubDomains"
+	}
+
+	return config
+}
+
+func SecurityHeadersMiddleware(config SecurityHeadersConfig, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for name, value := range config {
+			if value != "" {
+				w.Header().Set(name, value)
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,67 @@
+package moderation
+
+import (
+	"strings"
+	"unicode"
+)
+
+// confusables folds Cyrillic letters into the Latin letters they look like,
+// so "Тeam" with a Cyrillic "Т" and "Team" get the same key
+var confusables = map[rune]rune{
+	'а': 'a', 'в': 'b', 'е': 'e', 'ё': 'e', 'к': 'k', 'м': 'm', 'н': 'h',
+	'о': 'o', 'р': 'p', 'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'і': 'i',
+	'ї': 'i', 'ј': 'j', 'ѕ': 's', 'ԁ': 'd', 'ԛ': 'q', 'ԝ': 'w',
+}
+
+// leet maps digits and symbols used in place of letters, only applied when looking for banned words
+var leet = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '6': 'b', '7': 't', '8': 'b',
+	'@': 'a', '$': 's', '!': 'i', '|': 'i',
+}
+
+func fold(text string) []rune {
+	var result []rune
+	for _, char := range strings.ToLower(text) {
+		if folded, ok := confusables[char]; ok {
+			char = folded
+		}
+		result = append(result, char)
+	}
+
+	return result
+}
+
+// NameKey is the form team names are compared in for uniqueness:
+// case and look-alike letters are folded and whitespace is collapsed
+func NameKey(name string) string {
+	return strings.Join(strings.Fields(string(fold(name))), " ")
+}
+
+// skeleton keeps only the letters of the folded text, so separators like "b.a.d" don't hide a word
+func skeleton(text string) string {
+	var builder strings.Builder
+	for _, char := range fold(text) {
+		if replaced, ok := leet[char]; ok {
+			char = replaced
+		}
+		if unicode.IsLetter(char) {
+			builder.WriteRune(char)
+		}
+	}
+
+	return builder.String()
+}
+
+// FindBanned returns the banned words contained in the text
+func FindBanned(text string, banned []string) []string {
+	found := []string{}
+	textSkeleton := skeleton(text)
+	for _, word := range banned {
+		wordSkeleton := skeleton(word)
+		if wordSkeleton != "" && strings.Contains(textSkeleton, wordSkeleton) {
+			found = append(found, word)
+		}
+	}
+
+	return found
+}
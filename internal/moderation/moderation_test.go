@@ -0,0 +1,64 @@
+package moderation_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/SomeSuperCoder/global-chat/internal/moderation"
+)
+
+func TestNameKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{"case", "Team", "TEAM", true},
+		{"cyrillic look-alikes", "Тeam", "Team", true},
+		{"all cyrillic", "Тесо", "Teco", true},
+		{"whitespace", "  Red   Team ", "red team", true},
+		{"different words", "Red Team", "Blue Team", false},
+		{"spaces are kept between words", "Red Team", "RedTeam", false},
+		{"digits are not folded", "Team 1", "Team i", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, b := moderation.NameKey(test.a), moderation.NameKey(test.b)
+			if (a == b) != test.same {
+				t.Errorf("NameKey(%q) = %q, NameKey(%q) = %q, same = %v, want %v", test.a, a, test.b, b, a == b, test.same)
+			}
+		})
+	}
+}
+
+func TestFindBanned(t *testing.T) {
+	banned := []string{"bad", "evil", "..."}
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Good Team", []string{}},
+		{"Bat Team", []string{}},
+		{"Bad Team", []string{"bad"}},
+		{"BAD", []string{"bad"}},
+		{"b.a.d", []string{"bad"}},
+		{"b a d", []string{"bad"}},
+		{"b4d", []string{"bad"}},
+		{"8@d", []string{"bad"}},
+		{"еvil", []string{"evil"}},
+		{"3v!l and b_a_d", []string{"bad", "evil"}},
+		{"...", []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			got := moderation.FindBanned(test.text, banned)
+			slices.Sort(got)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("FindBanned(%q) = %v, want %v", test.text, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/mymmrac/telego"
+	tu "github.com/mymmrac/telego/telegoutil"
+	"github.com/sirupsen/logrus"
+)
+
+// Notifier sends bot messages from the API process.
+// Without a valid TELEGRAM_TOKEN messages are only logged.
+type Notifier struct {
+	bot *telego.Bot
+}
+
+func New() *Notifier {
+	bot, err := telego.NewBot(os.Getenv("TELEGRAM_TOKEN"), telego.WithDiscardLogger())
+	if err != nil {
+		logrus.Warnf("Bot notifications are disabled: %v", err)
+		return &Notifier{}
+	}
+
+	return &Notifier{
+		bot: bot,
+	}
+}
+
+// Telegram rejects longer messages
+const maxMessageLength = 4096
+
+// Send delivers the message in the background so handlers don't wait for Telegram.
+// Long texts are split into several messages sent in order.
+func (n *Notifier) Send(chatID int64, text string) {
+	parts := split(text, maxMessageLength)
+	messages := make([]*telego.SendMessageParams, len(parts))
+	for i, part := range parts {
+		messages[i] = tu.Message(tu.ID(chatID), part)
+	}
+
+	n.send(messages...)
+}
+
+func (n *Notifier) SendMessage(message *telego.SendMessageParams) {
+	n.send(message)
+}
+
+func (n *Notifier) send(messages ...*telego.SendMessageParams) {
+	if len(messages) == 0 {
+		return
+	}
+	if n.bot == nil || messages[0].ChatID.ID == 0 {
+		for _, message := range messages {
+			logrus.Infof("Notification to %v skipped: %s", message.ChatID, message.Text)
+		}
+		return
+	}
+
+	go func() {
+		for _, message := range messages {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			_, err := n.bot.SendMessage(ctx, message)
+			cancel()
+			if err != nil {
+				logrus.Errorf("Failed to notify %v: %v", message.ChatID, err)
+				return
+			}
+		}
+	}()
+}
+
+// split cuts the text into parts of at most limit characters, preferring line breaks
+func split(text string, limit int) []string {
+	runes := []rune(text)
+	parts := []string{}
+	for len(runes) > limit {
+		cut := limit
+		for i := limit; i > limit/2; i-- {
+			if runes[i-1] == '\n' {
+				cut = i
+				break
+			}
+		}
+		parts = append(parts, string(runes[:cut]))
+		runes = runes[cut:]
+	}
+
+	return append(parts, string(runes))
+}
+
+func (n *Notifier) SendToUsers(users []models.User, text string) {
+	for _, user := range users {
+		n.Send(user.ChatID, text)
+	}
+}
@@ -0,0 +1,254 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// JudgeProgress counts the team and criterion pairs a judge has to score and has scored
+type JudgeProgress struct {
+	Judge       bson.ObjectID `json:"judge"`
+	Name        string        `json:"name"`
+	Username    string        `json:"username"`
+	ChatID      int64         `json:"-"`
+	Teams       int           `json:"teams"`
+	ScoredTeams int           `json:"scored_teams"`
+	Expected    int           `json:"expected"`
+	Scored      int           `json:"scored"`
+	Missing     []MissingTeam `json:"missing"`
+	// Nil if the judge hasn't scored anything yet
+	LastScoredAt *time.Time `json:"last_scored_at"`
+}
+
+// MissingTeam lists the criteria the judge hasn't scored the team on
+type MissingTeam struct {
+	Team     bson.ObjectID   `json:"team"`
+	Name     string          `json:"name"`
+	Criteria []bson.ObjectID `json:"criteria"`
+}
+
+func (p *JudgeProgress) Done() bool {
+	return p.Scored >= p.Expected
+}
+
+func (p *JudgeProgress) ratio() float64 {
+	if p.Expected == 0 {
+		return 1
+	}
+
+	return float64(p.Scored) / float64(p.Expected)
+}
+
+type Input struct {
+	Judges   []models.User
+	Teams    []models.Team
+	Criteria []models.Criterion
+	// Teams each judge has to score
+	Assigned map[bson.ObjectID][]bson.ObjectID
+	Scores   []models.Score
+}
+
+// Load collects the progress of the judges in the round, a nil round means scoring outside of rounds.
+// Judges of the round score every team of the round, otherwise judges score the teams assigned to them
+// and the teams working on their case.
+func Load(ctx context.Context, userRepo *repository.UserRepo, teamRepo *repository.TeamRepo, criterionRepo *repository.CriterionRepo, assignmentRepo *repository.AssignmentRepo, caseRepo *repository.CaseRepo, scoreRepo *repository.ScoreRepo, round *models.Round) ([]JudgeProgress, error) {
+	var in Input
+	var err error
+
+	in.Teams, err = teamRepo.Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roundID := bson.NilObjectID
+	if round == nil {
+		in.Criteria, err = criterionRepo.Find(ctx)
+	} else {
+		roundID = round.ID
+		in.Criteria, err = criterionRepo.FindByIDs(ctx, round.Criteria)
+		teams := in.Teams[:0]
+		for _, team := range in.Teams {
+			if round.HasTeam(team.ID) {
+				teams = append(teams, team)
+			}
+		}
+		in.Teams = teams
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	in.Assigned = make(map[bson.ObjectID][]bson.ObjectID)
+	if round != nil && len(round.Judges) > 0 {
+		in.Judges, err = userRepo.FindByIDs(ctx, round.Judges)
+		if err != nil {
+			return nil, err
+		}
+		for _, judge := range in.Judges {
+			for _, team := range in.Teams {
+				in.Assigned[judge.ID] = append(in.Assigned[judge.ID], team.ID)
+			}
+		}
+	} else {
+		in.Judges, err = userRepo.FindByRole(ctx, models.Judge)
+		if err != nil {
+			return nil, err
+		}
+		assignments, err := assignmentRepo.Find(ctx)
+		if err != nil {
+			return nil, err
+		}
+		cases, err := caseRepo.Find(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		assigned := make(map[bson.ObjectID]map[bson.ObjectID]bool)
+		assign := func(judge, team bson.ObjectID) {
+			if assigned[judge] == nil {
+				assigned[judge] = make(map[bson.ObjectID]bool)
+			}
+			if !assigned[judge][team] {
+				assigned[judge][team] = true
+				in.Assigned[judge] = append(in.Assigned[judge], team)
+			}
+		}
+		for _, assignment := range assignments {
+			assign(assignment.Judge, assignment.Team)
+		}
+		for _, c := range cases {
+			for _, team := range in.Teams {
+				if team.Case != c.ID {
+					continue
+				}
+				for _, judge := range c.Judges {
+					assign(judge, team.ID)
+				}
+			}
+		}
+	}
+
+	in.Scores, err = scoreRepo.FindBy(ctx, roundID, bson.NilObjectID, bson.NilObjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return Compute(in), nil
+}
+
+// Compute returns the progress of every judge, the most lagging judges first
+func Compute(in Input) []JudgeProgress {
+	teams := make(map[bson.ObjectID]*models.Team, len(in.Teams))
+	for i := range in.Teams {
+		teams[in.Teams[i].ID] = &in.Teams[i]
+	}
+
+	type key struct {
+		judge, team, criterion bson.ObjectID
+	}
+	scored := make(map[key]bool, len(in.Scores))
+	lastScored := make(map[bson.ObjectID]time.Time)
+	for _, score := range in.Scores {
+		scored[key{score.Judge, score.Team, score.Criterion}] = true
+		if score.UpdatedAt.After(lastScored[score.Judge]) {
+			lastScored[score.Judge] = score.UpdatedAt
+		}
+	}
+
+	progress := make([]JudgeProgress, 0, len(in.Judges))
+	for _, judge := range in.Judges {
+		p := JudgeProgress{
+			Judge:    judge.ID,
+			Name:     judge.Name,
+			Username: judge.Username,
+			ChatID:   judge.ChatID,
+			Missing:  []MissingTeam{},
+		}
+		if last, ok := lastScored[judge.ID]; ok {
+			p.LastScoredAt = &last
+		}
+
+		for _, teamID := range in.Assigned[judge.ID] {
+			team, ok := teams[teamID]
+			if !ok {
+				continue
+			}
+			p.Teams++
+
+			missing := []bson.ObjectID{}
+			for _, criterion := range in.Criteria {
+				if !criterion.AppliesTo(team.Case) {
+					continue
+				}
+				p.Expected++
+				if scored[key{judge.ID, team.ID, criterion.ID}] {
+					p.Scored++
+				} else {
+					missing = append(missing, criterion.ID)
+				}
+			}
+
+			if len(missing) == 0 {
+				p.ScoredTeams++
+			} else {
+				p.Missing = append(p.Missing, MissingTeam{
+					Team:     team.ID,
+					Name:     team.Name,
+					Criteria: missing,
+				})
+			}
+		}
+
+		progress = append(progress, p)
+	}
+
+	sort.SliceStable(progress, func(i, j int) bool {
+		if progress[i].ratio() != progress[j].ratio() {
+			return progress[i].ratio() < progress[j].ratio()
+		}
+		return progress[i].Name < progress[j].Name
+	})
+
+	return progress
+}
+
+// Summary formats the progress of the judges for the admins in the bot
+func Summary(progress []JudgeProgress) string {
+	if len(progress) == 0 {
+		return "Судьи ещё не назначены"
+	}
+
+	var builder strings.Builder
+	builder.WriteString("Прогресс судей:\n")
+	for _, p := range progress {
+		status := "✅"
+		if !p.Done() {
+			status = "⏳"
+		}
+		builder.WriteString(fmt.Sprintf("\n%s %s (@%s): %d из %d оценок, команд оценено %d из %d", status, p.Name, p.Username, p.Scored, p.Expected, p.ScoredTeams, p.Teams))
+		if p.LastScoredAt != nil {
+			builder.WriteString(fmt.Sprintf(", последняя оценка %s", p.LastScoredAt.Local().Format("02.01 15:04")))
+		}
+	}
+
+	return builder.String()
+}
+
+// Reminder is the text sent to a lagging judge
+func Reminder(p *JudgeProgress) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Напоминание: осталось оценить команд — %d\n", len(p.Missing)))
+	for _, team := range p.Missing {
+		builder.WriteString(fmt.Sprintf("\n• %s: не хватает оценок по критериям — %d", team.Name, len(team.Criteria)))
+	}
+	builder.WriteString("\n\nПожалуйста, выставьте оценки в мини-приложении")
+
+	return builder.String()
+}
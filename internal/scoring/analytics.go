@@ -0,0 +1,260 @@
+package scoring
+
+import (
+	"math"
+	"sort"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// DefaultOutlierThreshold flags grades more than 30% of the criterion range away from the other judges
+const DefaultOutlierThreshold = 0.3
+
+type AnalyticsInput struct {
+	Teams    []models.Team
+	Criteria []models.Criterion
+	Judges   []models.User
+	Grades   []Grade
+	// Share of the criterion range a grade may differ from the other judges before it is flagged
+	Threshold float64
+}
+
+type Analytics struct {
+	Criteria []CriterionReliability `json:"criteria"`
+	Judges   []JudgeDeviation       `json:"judges"`
+	Outliers []Outlier              `json:"outliers"`
+}
+
+// CriterionReliability measures how much the judges agree on the criterion.
+// ICC is the one-way random effects ICC(1) over the teams graded by at least two judges,
+// nil when there is not enough data.
+type CriterionReliability struct {
+	Criterion bson.ObjectID `json:"criterion"`
+	Name      string        `json:"name"`
+	ICC       *float64      `json:"icc"`
+	Teams     int           `json:"teams"`
+	Grades    int           `json:"grades"`
+}
+
+// JudgeDeviation compares a judge with the other judges of the same teams, as a share of the criterion range.
+// A positive bias means the judge grades higher than the others.
+type JudgeDeviation struct {
+	Judge            bson.ObjectID `json:"judge"`
+	Name             string        `json:"name"`
+	Grades           int           `json:"grades"`
+	Bias             float64       `json:"bias"`
+	MeanAbsDeviation float64       `json:"mean_abs_deviation"`
+	Outliers         int           `json:"outliers"`
+}
+
+// Outlier is a grade far from the median of the other judges on the same team and criterion
+type Outlier struct {
+	Judge     bson.ObjectID `json:"judge"`
+	JudgeName string        `json:"judge_name"`
+	Team      bson.ObjectID `json:"team"`
+	TeamName  string        `json:"team_name"`
+	Criterion bson.ObjectID `json:"criterion"`
+	Value     float64       `json:"value"`
+	Consensus float64       `json:"consensus"`
+	Deviation float64       `json:"deviation"`
+}
+
+// Analyze computes the agreement between the judges. Outliers are only flagged when
+// at least two other judges graded the same team, otherwise there is no majority to compare with.
+func Analyze(input AnalyticsInput) Analytics {
+	threshold := input.Threshold
+	if threshold <= 0 {
+		threshold = DefaultOutlierThreshold
+	}
+
+	teamNames := make(map[bson.ObjectID]string, len(input.Teams))
+	for _, team := range input.Teams {
+		teamNames[team.ID] = team.Name
+	}
+	judgeNames := make(map[bson.ObjectID]string, len(input.Judges))
+	for _, judge := range input.Judges {
+		judgeNames[judge.ID] = judge.Name
+	}
+
+	// Grades of every criterion and team
+	type cell struct {
+		judges []bson.ObjectID
+		values []float64
+	}
+	cells := make(map[bson.ObjectID]map[bson.ObjectID]*cell, len(input.Criteria))
+	for _, criterion := range input.Criteria {
+		cells[criterion.ID] = make(map[bson.ObjectID]*cell)
+	}
+	for _, grade := range input.Grades {
+		byTeam, ok := cells[grade.Criterion]
+		if !ok {
+			continue
+		}
+		if _, ok := teamNames[grade.Team]; !ok {
+			continue
+		}
+		c := byTeam[grade.Team]
+		if c == nil {
+			c = &cell{}
+			byTeam[grade.Team] = c
+		}
+		c.judges = append(c.judges, grade.Judge)
+		c.values = append(c.values, grade.Value)
+	}
+
+	analytics := Analytics{
+		Criteria: make([]CriterionReliability, 0, len(input.Criteria)),
+		Judges:   []JudgeDeviation{},
+		Outliers: []Outlier{},
+	}
+
+	type deviation struct {
+		count    int
+		sum, abs float64
+		outliers int
+	}
+	deviations := make(map[bson.ObjectID]*deviation)
+
+	for _, criterion := range input.Criteria {
+		minScore, maxScore := criterion.ScoreRange()
+		span := float64(maxScore - minScore)
+		if span <= 0 {
+			span = 1
+		}
+
+		reliability := CriterionReliability{
+			Criterion: criterion.ID,
+			Name:      criterion.Text,
+		}
+		groups := [][]float64{}
+
+		// Visit the teams in a stable order so the outliers are listed consistently
+		teams := make([]bson.ObjectID, 0, len(cells[criterion.ID]))
+		for team := range cells[criterion.ID] {
+			teams = append(teams, team)
+		}
+		sort.Slice(teams, func(i, j int) bool {
+			return teams[i].Hex() < teams[j].Hex()
+		})
+
+		for _, team := range teams {
+			c := cells[criterion.ID][team]
+			reliability.Grades += len(c.values)
+			if len(c.values) < 2 {
+				continue
+			}
+			reliability.Teams++
+			groups = append(groups, c.values)
+
+			for i, judge := range c.judges {
+				others := make([]float64, 0, len(c.values)-1)
+				others = append(others, c.values[:i]...)
+				others = append(others, c.values[i+1:]...)
+				consensus := median(others)
+				share := (c.values[i] - consensus) / span
+
+				d := deviations[judge]
+				if d == nil {
+					d = &deviation{}
+					deviations[judge] = d
+				}
+				d.count++
+				d.sum += share
+				d.abs += math.Abs(share)
+
+				if len(others) >= 2 && math.Abs(share) > threshold+epsilon {
+					d.outliers++
+					analytics.Outliers = append(analytics.Outliers, Outlier{
+						Judge:     judge,
+						JudgeName: judgeNames[judge],
+						Team:      team,
+						TeamName:  teamNames[team],
+						Criterion: criterion.ID,
+						Value:     c.values[i],
+						Consensus: consensus,
+						Deviation: share,
+					})
+				}
+			}
+		}
+
+		reliability.ICC = icc1(groups)
+		analytics.Criteria = append(analytics.Criteria, reliability)
+	}
+
+	for judge, d := range deviations {
+		analytics.Judges = append(analytics.Judges, JudgeDeviation{
+			Judge:            judge,
+			Name:             judgeNames[judge],
+			Grades:           d.count,
+			Bias:             d.sum / float64(d.count),
+			MeanAbsDeviation: d.abs / float64(d.count),
+			Outliers:         d.outliers,
+		})
+	}
+	// The judges who disagree the most come first
+	sort.Slice(analytics.Judges, func(i, j int) bool {
+		a, b := analytics.Judges[i], analytics.Judges[j]
+		if c := compareFloat(a.MeanAbsDeviation, b.MeanAbsDeviation); c != 0 {
+			return c > 0
+		}
+		return a.Judge.Hex() < b.Judge.Hex()
+	})
+	sort.SliceStable(analytics.Outliers, func(i, j int) bool {
+		return math.Abs(analytics.Outliers[i].Deviation) > math.Abs(analytics.Outliers[j].Deviation)
+	})
+
+	return analytics
+}
+
+// icc1 is the one-way random effects intraclass correlation for groups of different sizes
+func icc1(groups [][]float64) *float64 {
+	n := len(groups)
+	total := 0
+	squares := 0
+	sum := 0.0
+	for _, values := range groups {
+		total += len(values)
+		squares += len(values) * len(values)
+		for _, value := range values {
+			sum += value
+		}
+	}
+	if n < 2 || total-n < 1 {
+		return nil
+	}
+	grand := sum / float64(total)
+
+	var between, within float64
+	for _, values := range groups {
+		mean, _ := meanStd(values)
+		between += float64(len(values)) * (mean - grand) * (mean - grand)
+		for _, value := range values {
+			within += (value - mean) * (value - mean)
+		}
+	}
+
+	msb := between / float64(n-1)
+	msw := within / float64(total-n)
+	k0 := (float64(total) - float64(squares)/float64(total)) / float64(n-1)
+	denominator := msb + (k0-1)*msw
+	if math.Abs(denominator) < epsilon {
+		return nil
+	}
+
+	icc := (msb - msw) / denominator
+	return &icc
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+
+	return sorted[middle]
+}
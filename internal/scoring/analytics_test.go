@@ -0,0 +1,206 @@
+package scoring_test
+
+import (
+	"testing"
+
+	"github.com/SomeSuperCoder/global-chat/internal/scoring"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func analyze(grades []scoring.Grade, threshold float64) scoring.Analytics {
+	return scoring.Analyze(scoring.AnalyticsInput{
+		Teams:    []models.Team{teamA, teamB, teamC},
+		Criteria: []models.Criterion{design},
+		Judges: []models.User{
+			{ID: judge1, Name: "Judge 1"},
+			{ID: judge2, Name: "Judge 2"},
+			{ID: judge3, Name: "Judge 3"},
+		},
+		Grades:    grades,
+		Threshold: threshold,
+	})
+}
+
+func TestAnalyzeICC(t *testing.T) {
+	tests := []struct {
+		name   string
+		grades []scoring.Grade
+		// Nil when there is not enough data
+		want  *float64
+		teams int
+	}{
+		{
+			name: "perfect agreement",
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 2), grade(judge2, teamA, design, 2),
+				grade(judge1, teamB, design, 8), grade(judge2, teamB, design, 8),
+			},
+			want:  ptr(1),
+			teams: 2,
+		},
+		{
+			name: "balanced groups",
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 1), grade(judge2, teamA, design, 3),
+				grade(judge1, teamB, design, 5), grade(judge2, teamB, design, 7),
+			},
+			want:  ptr(14.0 / 18),
+			teams: 2,
+		},
+		{
+			name: "unbalanced groups",
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 1), grade(judge2, teamA, design, 3), grade(judge3, teamA, design, 5),
+				grade(judge1, teamB, design, 6), grade(judge2, teamB, design, 8),
+			},
+			want:  ptr(0.6648044692737429),
+			teams: 2,
+		},
+		{
+			name: "teams graded by a single judge don't count",
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 1), grade(judge2, teamA, design, 3),
+				grade(judge1, teamB, design, 5), grade(judge2, teamB, design, 7),
+				grade(judge1, teamC, design, 10),
+			},
+			want:  ptr(14.0 / 18),
+			teams: 2,
+		},
+		{
+			name: "a single judge",
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 2), grade(judge1, teamB, design, 8),
+			},
+		},
+		{
+			name: "a single team",
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 2), grade(judge2, teamA, design, 4),
+			},
+			teams: 1,
+		},
+		{
+			name: "constant grades",
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 5), grade(judge2, teamA, design, 5),
+				grade(judge1, teamB, design, 5), grade(judge2, teamB, design, 5),
+			},
+			teams: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reliability := analyze(test.grades, 0).Criteria[0]
+			if reliability.Teams != test.teams || reliability.Grades != len(test.grades) {
+				t.Errorf("teams %d, grades %d, want %d and %d", reliability.Teams, reliability.Grades, test.teams, len(test.grades))
+			}
+
+			switch {
+			case test.want == nil && reliability.ICC != nil:
+				t.Errorf("ICC = %v, want nil", *reliability.ICC)
+			case test.want != nil && reliability.ICC == nil:
+				t.Errorf("ICC = nil, want %v", *test.want)
+			case test.want != nil && !near(*reliability.ICC, *test.want):
+				t.Errorf("ICC = %v, want %v", *reliability.ICC, *test.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeOutliers(t *testing.T) {
+	tests := []struct {
+		name      string
+		grades    []scoring.Grade
+		threshold float64
+		// Flagged judges, the largest deviation first
+		want []bson.ObjectID
+	}{
+		{
+			name: "a grade far from the others is flagged",
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 5), grade(judge2, teamA, design, 5), grade(judge3, teamA, design, 10),
+			},
+			want: []bson.ObjectID{judge3},
+		},
+		{
+			name: "two judges have no majority",
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 0), grade(judge2, teamA, design, 10),
+			},
+		},
+		{
+			name: "the threshold itself is not an outlier",
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 5), grade(judge2, teamA, design, 5), grade(judge3, teamA, design, 8),
+			},
+		},
+		{
+			name: "custom threshold",
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 5), grade(judge2, teamA, design, 5), grade(judge3, teamA, design, 7),
+			},
+			threshold: 0.1,
+			want:      []bson.ObjectID{judge3},
+		},
+		{
+			name: "constant grades",
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 5), grade(judge2, teamA, design, 5), grade(judge3, teamA, design, 5),
+			},
+		},
+		{
+			name: "grades of unknown teams are ignored",
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 5), grade(judge2, teamA, design, 5),
+				{Judge: judge3, Team: bson.NewObjectID(), Criterion: design.ID, Value: 10},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outliers := analyze(test.grades, test.threshold).Outliers
+			if len(outliers) != len(test.want) {
+				t.Fatalf("got %d outliers, want %d", len(outliers), len(test.want))
+			}
+			for i, judge := range test.want {
+				if outliers[i].Judge != judge {
+					t.Errorf("outlier %d: judge %s, want %s", i, outliers[i].JudgeName, judge.Hex())
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeJudgeDeviation(t *testing.T) {
+	analytics := analyze([]scoring.Grade{
+		grade(judge1, teamA, design, 5), grade(judge2, teamA, design, 5), grade(judge3, teamA, design, 10),
+		grade(judge1, teamB, design, 4), grade(judge2, teamB, design, 4), grade(judge3, teamB, design, 4),
+	}, 0)
+
+	want := map[bson.ObjectID]scoring.JudgeDeviation{
+		// Compared with the median of 5 and 10 on team A, in line on team B
+		judge1: {Grades: 2, Bias: -0.125, MeanAbsDeviation: 0.125},
+		judge2: {Grades: 2, Bias: -0.125, MeanAbsDeviation: 0.125},
+		judge3: {Grades: 2, Bias: 0.25, MeanAbsDeviation: 0.25, Outliers: 1},
+	}
+
+	if len(analytics.Judges) != len(want) {
+		t.Fatalf("got %d judges, want %d", len(analytics.Judges), len(want))
+	}
+	if analytics.Judges[0].Judge != judge3 {
+		t.Errorf("judge %s listed first, want the one who disagrees the most", analytics.Judges[0].Name)
+	}
+	for _, got := range analytics.Judges {
+		w := want[got.Judge]
+		if got.Grades != w.Grades || !near(got.Bias, w.Bias) || !near(got.MeanAbsDeviation, w.MeanAbsDeviation) || got.Outliers != w.Outliers {
+			t.Errorf("%s: got %+v, want %+v", got.Name, got, w)
+		}
+	}
+}
+
+func ptr(value float64) *float64 {
+	return &value
+}
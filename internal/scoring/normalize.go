@@ -0,0 +1,175 @@
+package scoring
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Normalization methods, each applied per judge and criterion so harsh and lenient judges weigh the same
+const (
+	// Grades as given
+	MethodRaw = "raw"
+	// Standard score of the judge, mapped onto the spread of all judges
+	MethodZScore = "zscore"
+	// Lowest grade of the judge becomes the minimum of the criterion, highest the maximum
+	MethodMinMax = "minmax"
+	// Teams get points for every team the judge graded lower (Borda count), scaled onto the criterion range
+	MethodBorda = "borda"
+)
+
+var Methods = []string{MethodRaw, MethodZScore, MethodMinMax, MethodBorda}
+
+func ValidMethod(method string) bool {
+	for _, known := range Methods {
+		if method == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+type groupKey struct {
+	judge     bson.ObjectID
+	criterion bson.ObjectID
+}
+
+// Normalize returns a copy of the grades converted with the method, an empty method means raw
+func Normalize(grades []Grade, criteria []models.Criterion, method string) ([]Grade, error) {
+	if method == "" {
+		method = MethodRaw
+	}
+	if !ValidMethod(method) {
+		return nil, fmt.Errorf("unknown normalization method %q", method)
+	}
+
+	normalized := make([]Grade, len(grades))
+	copy(normalized, grades)
+	if method == MethodRaw {
+		return normalized, nil
+	}
+
+	ranges := make(map[bson.ObjectID][2]float64, len(criteria))
+	for _, criterion := range criteria {
+		minScore, maxScore := criterion.ScoreRange()
+		ranges[criterion.ID] = [2]float64{float64(minScore), float64(maxScore)}
+	}
+
+	groups := make(map[groupKey][]int)
+	byCriterion := make(map[bson.ObjectID][]float64)
+	for i, grade := range grades {
+		key := groupKey{judge: grade.Judge, criterion: grade.Criterion}
+		groups[key] = append(groups[key], i)
+		byCriterion[grade.Criterion] = append(byCriterion[grade.Criterion], grade.Value)
+	}
+
+	for key, indexes := range groups {
+		values := make([]float64, len(indexes))
+		for i, index := range indexes {
+			values[i] = grades[index].Value
+		}
+
+		var converted []float64
+		bounds, ok := ranges[key.criterion]
+		switch {
+		case !ok:
+			converted = values
+		case method == MethodZScore:
+			mean, std := meanStd(byCriterion[key.criterion])
+			converted = zScore(values, mean, std)
+		case method == MethodMinMax:
+			converted = minMax(values, bounds)
+		case method == MethodBorda:
+			converted = borda(values, bounds)
+		}
+
+		for i, index := range indexes {
+			normalized[index].Value = converted[i]
+		}
+	}
+
+	return normalized, nil
+}
+
+func meanStd(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
+
+// zScore maps the standard score of every value onto the mean and spread of all judges
+func zScore(values []float64, mean, std float64) []float64 {
+	judgeMean, judgeStd := meanStd(values)
+
+	result := make([]float64, len(values))
+	for i, value := range values {
+		if judgeStd < epsilon {
+			result[i] = mean
+			continue
+		}
+		result[i] = mean + (value-judgeMean)/judgeStd*std
+	}
+
+	return result
+}
+
+func minMax(values []float64, bounds [2]float64) []float64 {
+	low, high := values[0], values[0]
+	for _, value := range values {
+		low = math.Min(low, value)
+		high = math.Max(high, value)
+	}
+
+	result := make([]float64, len(values))
+	for i, value := range values {
+		share := 0.5
+		if high-low >= epsilon {
+			share = (value - low) / (high - low)
+		}
+		result[i] = bounds[0] + share*(bounds[1]-bounds[0])
+	}
+
+	return result
+}
+
+// borda gives a point for every lower value and half a point for every tie
+func borda(values []float64, bounds [2]float64) []float64 {
+	result := make([]float64, len(values))
+	for i, value := range values {
+		share := 0.5
+		if len(values) > 1 {
+			var points float64
+			for j, other := range values {
+				if i == j {
+					continue
+				}
+				switch compareFloat(value, other) {
+				case 1:
+					points++
+				case 0:
+					points += 0.5
+				}
+			}
+			share = points / float64(len(values)-1)
+		}
+		result[i] = bounds[0] + share*(bounds[1]-bounds[0])
+	}
+
+	return result
+}
@@ -0,0 +1,165 @@
+package scoring_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/SomeSuperCoder/global-chat/internal/scoring"
+	"github.com/SomeSuperCoder/global-chat/models"
+)
+
+func TestNormalize(t *testing.T) {
+	sqrt5 := math.Sqrt(5)
+	sqrt6 := math.Sqrt(6)
+	legacy := models.Criterion{ID: code.ID}
+
+	tests := []struct {
+		name     string
+		method   string
+		criteria []models.Criterion
+		grades   []scoring.Grade
+		want     []float64
+	}{
+		{
+			name:     "empty method is raw",
+			criteria: []models.Criterion{design},
+			grades:   []scoring.Grade{grade(judge1, teamA, design, 3), grade(judge1, teamB, design, 7)},
+			want:     []float64{3, 7},
+		},
+		{
+			// Criterion mean 5 and spread sqrt(5), both judges spread their grades the same way
+			name:     "zscore aligns harsh and lenient judges",
+			method:   scoring.MethodZScore,
+			criteria: []models.Criterion{design},
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 2),
+				grade(judge1, teamB, design, 4),
+				grade(judge2, teamA, design, 6),
+				grade(judge2, teamB, design, 8),
+			},
+			want: []float64{5 - sqrt5, 5 + sqrt5, 5 - sqrt5, 5 + sqrt5},
+		},
+		{
+			name:     "zscore of a constant judge is the criterion mean",
+			method:   scoring.MethodZScore,
+			criteria: []models.Criterion{design},
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 3),
+				grade(judge1, teamB, design, 3),
+				grade(judge2, teamA, design, 9),
+				grade(judge2, teamB, design, 5),
+			},
+			// Criterion mean 5 and spread sqrt(6)
+			want: []float64{5, 5, 5 + sqrt6, 5 - sqrt6},
+		},
+		{
+			name:     "zscore of a single judge with a single grade",
+			method:   scoring.MethodZScore,
+			criteria: []models.Criterion{design},
+			grades:   []scoring.Grade{grade(judge1, teamA, design, 7)},
+			want:     []float64{7},
+		},
+		{
+			name:     "minmax stretches onto the criterion range",
+			method:   scoring.MethodMinMax,
+			criteria: []models.Criterion{design},
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 2),
+				grade(judge1, teamB, design, 4),
+				grade(judge1, teamC, design, 6),
+			},
+			want: []float64{0, 5, 10},
+		},
+		{
+			name:     "minmax of constant grades is the middle of the range",
+			method:   scoring.MethodMinMax,
+			criteria: []models.Criterion{design},
+			grades:   []scoring.Grade{grade(judge1, teamA, design, 3), grade(judge1, teamB, design, 3)},
+			want:     []float64{5, 5},
+		},
+		{
+			name:     "minmax per judge and criterion with unbalanced groups",
+			method:   scoring.MethodMinMax,
+			criteria: []models.Criterion{design, code},
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 1),
+				grade(judge1, teamB, design, 2),
+				grade(judge1, teamC, design, 3),
+				grade(judge2, teamA, design, 9),
+				grade(judge1, teamA, code, 8),
+				grade(judge1, teamB, code, 4),
+			},
+			want: []float64{0, 5, 10, 5, 10, 0},
+		},
+		{
+			name:     "borda counts the teams graded lower, ties count half",
+			method:   scoring.MethodBorda,
+			criteria: []models.Criterion{design},
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 1),
+				grade(judge1, teamB, design, 2),
+				grade(judge1, teamC, design, 2),
+			},
+			want: []float64{0, 7.5, 7.5},
+		},
+		{
+			name:     "borda of a single grade is the middle of the range",
+			method:   scoring.MethodBorda,
+			criteria: []models.Criterion{design},
+			grades:   []scoring.Grade{grade(judge1, teamA, design, 9)},
+			want:     []float64{5},
+		},
+		{
+			name:     "legacy criteria use the default range",
+			method:   scoring.MethodMinMax,
+			criteria: []models.Criterion{legacy},
+			grades:   []scoring.Grade{grade(judge1, teamA, legacy, 1), grade(judge1, teamB, legacy, 2)},
+			want:     []float64{0, float64(models.DefaultMaxScore)},
+		},
+		{
+			name:     "grades on unknown criteria are kept",
+			method:   scoring.MethodBorda,
+			criteria: []models.Criterion{design},
+			grades:   []scoring.Grade{grade(judge1, teamA, code, 4), grade(judge1, teamB, code, 6)},
+			want:     []float64{4, 6},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			normalized, err := scoring.Normalize(test.grades, test.criteria, test.method)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(normalized) != len(test.want) {
+				t.Fatalf("got %d grades, want %d", len(normalized), len(test.want))
+			}
+			for i, want := range test.want {
+				if !near(normalized[i].Value, want) {
+					t.Errorf("grade %d = %v, want %v", i, normalized[i].Value, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeKeepsInput(t *testing.T) {
+	grades := []scoring.Grade{grade(judge1, teamA, design, 2), grade(judge1, teamB, design, 4)}
+
+	for _, method := range scoring.Methods {
+		normalized, err := scoring.Normalize(grades, []models.Criterion{design}, method)
+		if err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		normalized[0].Value = 100
+		if grades[0].Value != 2 || grades[1].Value != 4 {
+			t.Fatalf("%s changed the input: %v", method, grades)
+		}
+	}
+}
+
+func TestNormalizeUnknownMethod(t *testing.T) {
+	if _, err := scoring.Normalize(nil, nil, "median"); err == nil {
+		t.Error("expected an error for an unknown method")
+	}
+}
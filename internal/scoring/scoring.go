@@ -0,0 +1,242 @@
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const epsilon = 1e-9
+
+// Grade is a single score given by a judge to a team on a criterion
+type Grade struct {
+	Judge     bson.ObjectID
+	Team      bson.ObjectID
+	Criterion bson.ObjectID
+	Value     float64
+}
+
+func GradesFromScores(scores []models.Score) []Grade {
+	grades := make([]Grade, 0, len(scores))
+	for _, score := range scores {
+		grades = append(grades, Grade{
+			Judge:     score.Judge,
+			Team:      score.Team,
+			Criterion: score.Criterion,
+			Value:     float64(score.Value),
+		})
+	}
+
+	return grades
+}
+
+type Input struct {
+	Teams    []models.Team
+	Criteria []models.Criterion
+	Grades   []Grade
+	// Number of judges expected to grade every team, used for the coverage.
+	// Assigned overrides it for teams with assigned judges.
+	JudgeCount int
+	Assigned   map[bson.ObjectID]int
+	TieBreaks  []TieBreak
+}
+
+type CriterionScore struct {
+	Criterion bson.ObjectID `json:"criterion"`
+	Average   float64       `json:"average"`
+	Weighted  float64       `json:"weighted"`
+	Grades    int           `json:"grades"`
+}
+
+type Standing struct {
+	Rank     int              `json:"rank"`
+	Team     bson.ObjectID    `json:"team"`
+	Name     string           `json:"name"`
+	Case     bson.ObjectID    `json:"case"`
+	Total    float64          `json:"total"`
+	Criteria []CriterionScore `json:"criteria"`
+	// Judges who graded the team and the share of expected grades given
+	Judges   int     `json:"judges"`
+	Coverage float64 `json:"coverage"`
+}
+
+// Rank computes the weighted totals and orders the teams.
+// Teams which stay equal after every tie-break share the rank.
+func Rank(input Input) []Standing {
+	type cell struct {
+		sum   float64
+		count int
+	}
+
+	cells := make(map[bson.ObjectID]map[bson.ObjectID]*cell)
+	judges := make(map[bson.ObjectID]map[bson.ObjectID]bool)
+	known := make(map[bson.ObjectID]bool, len(input.Criteria))
+	for _, criterion := range input.Criteria {
+		known[criterion.ID] = true
+	}
+
+	for _, grade := range input.Grades {
+		if !known[grade.Criterion] {
+			continue
+		}
+		if cells[grade.Team] == nil {
+			cells[grade.Team] = make(map[bson.ObjectID]*cell)
+			judges[grade.Team] = make(map[bson.ObjectID]bool)
+		}
+		c := cells[grade.Team][grade.Criterion]
+		if c == nil {
+			c = &cell{}
+			cells[grade.Team][grade.Criterion] = c
+		}
+		c.sum += grade.Value
+		c.count++
+		judges[grade.Team][grade.Judge] = true
+	}
+
+	standings := make([]Standing, 0, len(input.Teams))
+	for _, team := range input.Teams {
+		standing := Standing{
+			Team:     team.ID,
+			Name:     team.Name,
+			Case:     team.Case,
+			Criteria: make([]CriterionScore, 0, len(input.Criteria)),
+			Judges:   len(judges[team.ID]),
+		}
+
+		graded := 0
+		for _, criterion := range input.Criteria {
+			score := CriterionScore{
+				Criterion: criterion.ID,
+			}
+			if c := cells[team.ID][criterion.ID]; c != nil {
+				score.Average = c.sum / float64(c.count)
+				score.Weighted = score.Average * criterion.EffectiveWeight()
+				score.Grades = c.count
+				graded += c.count
+			}
+			standing.Total += score.Weighted
+			standing.Criteria = append(standing.Criteria, score)
+		}
+
+		judgeCount := input.JudgeCount
+		if assigned, ok := input.Assigned[team.ID]; ok {
+			judgeCount = assigned
+		}
+		expected := max(judgeCount, standing.Judges) * len(input.Criteria)
+		if expected > 0 {
+			standing.Coverage = float64(graded) / float64(expected)
+		}
+
+		standings = append(standings, standing)
+	}
+
+	compare := func(a, b *Standing) int {
+		if c := compareFloat(a.Total, b.Total); c != 0 {
+			return c
+		}
+		for _, tieBreak := range input.TieBreaks {
+			if c := tieBreak.compare(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+
+	// Names and IDs only make the order stable, they don't break ties
+	sort.SliceStable(standings, func(i, j int) bool {
+		if c := compare(&standings[i], &standings[j]); c != 0 {
+			return c > 0
+		}
+		if standings[i].Name != standings[j].Name {
+			return standings[i].Name < standings[j].Name
+		}
+		return standings[i].Team.Hex() < standings[j].Team.Hex()
+	})
+
+	for i := range standings {
+		if i > 0 && compare(&standings[i-1], &standings[i]) == 0 {
+			standings[i].Rank = standings[i-1].Rank
+		} else {
+			standings[i].Rank = i + 1
+		}
+	}
+
+	return standings
+}
+
+// compareFloat returns 1 if a is greater, -1 if it's less and 0 if they are equal
+func compareFloat(a, b float64) int {
+	switch {
+	case math.Abs(a-b) < epsilon:
+		return 0
+	case a > b:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// ========== Tie-breaks ==========
+
+const (
+	// Higher average on a criterion: "criterion:<id>"
+	TieBreakCriterion = "criterion"
+	// More of the expected grades given
+	TieBreakCoverage = "coverage"
+	// Graded by more judges
+	TieBreakJudges = "judges"
+)
+
+type TieBreak struct {
+	Kind      string
+	Criterion bson.ObjectID
+}
+
+// ParseTieBreaks reads the rules stored in the settings, e.g. ["criterion:<id>", "coverage"]
+func ParseTieBreaks(rules []string) ([]TieBreak, error) {
+	tieBreaks := make([]TieBreak, 0, len(rules))
+	for _, rule := range rules {
+		kind, argument, _ := strings.Cut(strings.TrimSpace(rule), ":")
+		switch kind {
+		case TieBreakCriterion:
+			id, err := bson.ObjectIDFromHex(argument)
+			if err != nil {
+				return nil, fmt.Errorf("invalid criterion in tie-break %q", rule)
+			}
+			tieBreaks = append(tieBreaks, TieBreak{Kind: kind, Criterion: id})
+		case TieBreakCoverage, TieBreakJudges:
+			tieBreaks = append(tieBreaks, TieBreak{Kind: kind})
+		default:
+			return nil, fmt.Errorf("unknown tie-break %q", rule)
+		}
+	}
+
+	return tieBreaks, nil
+}
+
+func (t TieBreak) compare(a, b *Standing) int {
+	switch t.Kind {
+	case TieBreakCriterion:
+		return compareFloat(criterionAverage(a, t.Criterion), criterionAverage(b, t.Criterion))
+	case TieBreakCoverage:
+		return compareFloat(a.Coverage, b.Coverage)
+	case TieBreakJudges:
+		return a.Judges - b.Judges
+	}
+
+	return 0
+}
+
+func criterionAverage(standing *Standing, criterion bson.ObjectID) float64 {
+	for _, score := range standing.Criteria {
+		if score.Criterion == criterion {
+			return score.Average
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,231 @@
+package scoring_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/SomeSuperCoder/global-chat/internal/scoring"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var (
+	teamA = models.Team{ID: bson.NewObjectID(), Name: "A"}
+	teamB = models.Team{ID: bson.NewObjectID(), Name: "B"}
+	teamC = models.Team{ID: bson.NewObjectID(), Name: "C"}
+
+	judge1 = bson.NewObjectID()
+	judge2 = bson.NewObjectID()
+	judge3 = bson.NewObjectID()
+
+	design = models.Criterion{ID: bson.NewObjectID(), Text: "Design", MinScore: 0, MaxScore: 10}
+	code   = models.Criterion{ID: bson.NewObjectID(), Text: "Code", MinScore: 0, MaxScore: 10}
+)
+
+func grade(judge bson.ObjectID, team models.Team, criterion models.Criterion, value float64) scoring.Grade {
+	return scoring.Grade{Judge: judge, Team: team.ID, Criterion: criterion.ID, Value: value}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+type ranked struct {
+	team models.Team
+	rank int
+}
+
+func TestRank(t *testing.T) {
+	weighted := design
+	weighted.Weight = 2
+
+	tests := []struct {
+		name       string
+		criteria   []models.Criterion
+		grades     []scoring.Grade
+		judgeCount int
+		tieBreaks  []string
+		want       []ranked
+	}{
+		{
+			name:     "equal totals share the rank",
+			criteria: []models.Criterion{design},
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 8),
+				grade(judge1, teamB, design, 8),
+				grade(judge1, teamC, design, 5),
+			},
+			want: []ranked{{teamA, 1}, {teamB, 1}, {teamC, 3}},
+		},
+		{
+			name:     "grades of several judges are averaged",
+			criteria: []models.Criterion{design},
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 10),
+				grade(judge2, teamA, design, 0),
+				grade(judge1, teamB, design, 4),
+			},
+			want: []ranked{{teamA, 1}, {teamB, 2}, {teamC, 3}},
+		},
+		{
+			name:     "weights scale the criteria",
+			criteria: []models.Criterion{weighted, code},
+			grades: []scoring.Grade{
+				grade(judge1, teamA, weighted, 5),
+				grade(judge1, teamA, code, 1),
+				grade(judge1, teamB, weighted, 3),
+				grade(judge1, teamB, code, 9),
+			},
+			want: []ranked{{teamB, 1}, {teamA, 2}, {teamC, 3}},
+		},
+		{
+			name:     "criterion tie-break",
+			criteria: []models.Criterion{design, code},
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 6),
+				grade(judge1, teamA, code, 4),
+				grade(judge1, teamB, design, 4),
+				grade(judge1, teamB, code, 6),
+			},
+			tieBreaks: []string{"criterion:" + code.ID.Hex()},
+			want:      []ranked{{teamB, 1}, {teamA, 2}, {teamC, 3}},
+		},
+		{
+			name:     "coverage tie-break",
+			criteria: []models.Criterion{design},
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 5),
+				grade(judge1, teamB, design, 5),
+				grade(judge2, teamB, design, 5),
+			},
+			judgeCount: 2,
+			tieBreaks:  []string{"coverage"},
+			want:       []ranked{{teamB, 1}, {teamA, 2}, {teamC, 3}},
+		},
+		{
+			name:     "judges tie-break",
+			criteria: []models.Criterion{design},
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 5),
+				grade(judge2, teamA, design, 5),
+				grade(judge3, teamA, design, 5),
+				grade(judge1, teamB, design, 5),
+			},
+			tieBreaks: []string{"judges"},
+			want:      []ranked{{teamA, 1}, {teamB, 2}, {teamC, 3}},
+		},
+		{
+			name:     "tie-breaks that don't separate keep the shared rank",
+			criteria: []models.Criterion{design},
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 5),
+				grade(judge1, teamB, design, 5),
+			},
+			tieBreaks: []string{"coverage", "judges"},
+			want:      []ranked{{teamA, 1}, {teamB, 1}, {teamC, 3}},
+		},
+		{
+			name:     "grades on unknown criteria are ignored",
+			criteria: []models.Criterion{design},
+			grades: []scoring.Grade{
+				grade(judge1, teamA, design, 5),
+				grade(judge1, teamB, design, 4),
+				grade(judge1, teamB, code, 10),
+			},
+			want: []ranked{{teamA, 1}, {teamB, 2}, {teamC, 3}},
+		},
+		{
+			name:     "nothing graded",
+			criteria: []models.Criterion{design},
+			want:     []ranked{{teamA, 1}, {teamB, 1}, {teamC, 1}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tieBreaks, err := scoring.ParseTieBreaks(test.tieBreaks)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			standings := scoring.Rank(scoring.Input{
+				Teams:      []models.Team{teamC, teamB, teamA},
+				Criteria:   test.criteria,
+				Grades:     test.grades,
+				JudgeCount: test.judgeCount,
+				TieBreaks:  tieBreaks,
+			})
+			if len(standings) != len(test.want) {
+				t.Fatalf("got %d standings, want %d", len(standings), len(test.want))
+			}
+			for i, want := range test.want {
+				got := standings[i]
+				if got.Team != want.team.ID || got.Rank != want.rank {
+					t.Errorf("place %d: got %s ranked %d, want %s ranked %d", i, got.Name, got.Rank, want.team.Name, want.rank)
+				}
+				if math.IsNaN(got.Total) || math.IsNaN(got.Coverage) {
+					t.Errorf("place %d: total %v, coverage %v", i, got.Total, got.Coverage)
+				}
+			}
+		})
+	}
+}
+
+func TestRankTotalsAndCoverage(t *testing.T) {
+	weighted := design
+	weighted.Weight = 2
+
+	standings := scoring.Rank(scoring.Input{
+		Teams:    []models.Team{teamA, teamB},
+		Criteria: []models.Criterion{weighted, code},
+		Grades: []scoring.Grade{
+			grade(judge1, teamA, weighted, 4),
+			grade(judge2, teamA, weighted, 6),
+			grade(judge1, teamA, code, 3),
+		},
+		JudgeCount: 2,
+		Assigned:   map[bson.ObjectID]int{teamB.ID: 1},
+	})
+
+	a, b := standings[0], standings[1]
+	// (4+6)/2*2 + 3
+	if !near(a.Total, 13) {
+		t.Errorf("total = %v, want 13", a.Total)
+	}
+	if a.Judges != 2 {
+		t.Errorf("judges = %d, want 2", a.Judges)
+	}
+	// 3 of 2 judges * 2 criteria
+	if !near(a.Coverage, 0.75) {
+		t.Errorf("coverage = %v, want 0.75", a.Coverage)
+	}
+	if b.Total != 0 || b.Judges != 0 || b.Coverage != 0 {
+		t.Errorf("ungraded team: total %v, judges %d, coverage %v", b.Total, b.Judges, b.Coverage)
+	}
+}
+
+func TestParseTieBreaks(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []string
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"every kind", []string{"criterion:" + design.ID.Hex(), " coverage ", "judges"}, false},
+		{"unknown kind", []string{"luck"}, true},
+		{"criterion without id", []string{"criterion"}, true},
+		{"criterion with bad id", []string{"criterion:xyz"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tieBreaks, err := scoring.ParseTieBreaks(test.rules)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, test.wantErr)
+			}
+			if err == nil && len(tieBreaks) != len(test.rules) {
+				t.Errorf("got %d tie-breaks, want %d", len(tieBreaks), len(test.rules))
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package teamrules
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+)
+
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Check reports every rule the team currently breaks
+func Check(rules *models.TeamRules, members []models.User, now time.Time) []Violation {
+	violations := []Violation{}
+
+	if rules.MinMembers > 0 && len(members) < rules.MinMembers {
+		violations = append(violations, Violation{
+			Rule:    "min_members",
+			Message: fmt.Sprintf("team has %d members, at least %d required", len(members), rules.MinMembers),
+		})
+	}
+
+	if rules.MaxMembers > 0 && len(members) > rules.MaxMembers {
+		violations = append(violations, Violation{
+			Rule:    "max_members",
+			Message: fmt.Sprintf("team has %d members, at most %d allowed", len(members), rules.MaxMembers),
+		})
+	}
+
+	return append(violations, checkComposition(rules, members, now)...)
+}
+
+// CheckJoin validates the members a team would have after a join.
+// The size limit is strict. Composition can still be fixed by later joins,
+// so it only blocks the join that fills the last free place.
+func CheckJoin(rules *models.TeamRules, members []models.User, now time.Time) []Violation {
+	if rules.MaxMembers <= 0 {
+		return []Violation{}
+	}
+
+	if len(members) > rules.MaxMembers {
+		return []Violation{TeamFull(rules)}
+	}
+
+	if len(members) == rules.MaxMembers {
+		return checkComposition(rules, members, now)
+	}
+
+	return []Violation{}
+}
+
+func CheckTeamCount(rules *models.TeamRules, count int64) []Violation {
+	if rules.MaxTeams > 0 && count >= int64(rules.MaxTeams) {
+		return []Violation{TooManyTeams(rules)}
+	}
+
+	return []Violation{}
+}
+
+func TeamFull(rules *models.TeamRules) Violation {
+	return Violation{
+		Rule:    "max_members",
+		Message: fmt.Sprintf("team is full: at most %d members allowed", rules.MaxMembers),
+	}
+}
+
+func TooManyTeams(rules *models.TeamRules) Violation {
+	return Violation{
+		Rule:    "max_teams",
+		Message: fmt.Sprintf("at most %d teams allowed", rules.MaxTeams),
+	}
+}
+
+func checkComposition(rules *models.TeamRules, members []models.User, now time.Time) []Violation {
+	violations := []Violation{}
+
+	for _, constraint := range rules.AgeConstraints {
+		matching := 0
+		for _, member := range members {
+			age := Age(member.Birthdate, now)
+			if age >= constraint.MinAge && (constraint.MaxAge == 0 || age < constraint.MaxAge) {
+				matching++
+			}
+		}
+
+		if matching < constraint.MinCount {
+			violations = append(violations, Violation{
+				Rule:    "age",
+				Message: fmt.Sprintf("at least %d members aged %s required, team has %d", constraint.MinCount, describeAgeRange(constraint), matching),
+			})
+		}
+	}
+
+	return violations
+}
+
+func describeAgeRange(constraint models.AgeConstraint) string {
+	if constraint.MaxAge == 0 {
+		return fmt.Sprintf("%d or older", constraint.MinAge)
+	}
+	if constraint.MinAge == 0 {
+		return fmt.Sprintf("under %d", constraint.MaxAge)
+	}
+	return fmt.Sprintf("%d to %d", constraint.MinAge, constraint.MaxAge-1)
+}
+
+func Age(birthdate time.Time, now time.Time) int {
+	age := now.Year() - birthdate.Year()
+	if now.Month() < birthdate.Month() || (now.Month() == birthdate.Month() && now.Day() < birthdate.Day()) {
+		age--
+	}
+	return age
+}
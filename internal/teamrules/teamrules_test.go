@@ -0,0 +1,147 @@
+package teamrules_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/internal/teamrules"
+	"github.com/SomeSuperCoder/global-chat/models"
+)
+
+var now = time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// aged returns members who turned the given ages today
+func aged(ages ...int) []models.User {
+	members := make([]models.User, len(ages))
+	for i, age := range ages {
+		members[i] = models.User{Birthdate: date(now.Year()-age, now.Month(), now.Day())}
+	}
+	return members
+}
+
+func rulesOf(violations []teamrules.Violation) []string {
+	rules := []string{}
+	for _, violation := range violations {
+		rules = append(rules, violation.Rule)
+	}
+	return rules
+}
+
+func TestAge(t *testing.T) {
+	tests := []struct {
+		name      string
+		birthdate time.Time
+		now       time.Time
+		want      int
+	}{
+		{"birthday today", date(2006, 6, 15), now, 18},
+		{"birthday tomorrow", date(2006, 6, 16), now, 17},
+		{"birthday yesterday", date(2006, 6, 14), now, 18},
+		{"birthday next month", date(2006, 7, 1), now, 17},
+		{"born on the 29th of February, not yet on the 28th", date(2004, 2, 29), date(2023, 2, 28), 18},
+		{"born on the 29th of February, counted on the 1st of March", date(2004, 2, 29), date(2023, 3, 1), 19},
+		{"born today", now, now, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := teamrules.Age(test.birthdate, test.now); got != test.want {
+				t.Errorf("Age = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	// At least one member under 18, MaxAge is exclusive
+	minor := models.AgeConstraint{MaxAge: 18, MinCount: 1}
+	// At least two members aged 18 or older
+	adults := models.AgeConstraint{MinAge: 18, MinCount: 2}
+
+	tests := []struct {
+		name    string
+		rules   models.TeamRules
+		members []models.User
+		want    []string
+	}{
+		{"no rules", models.TeamRules{}, aged(20), []string{}},
+		{"within limits", models.TeamRules{MinMembers: 2, MaxMembers: 3}, aged(20, 21), []string{}},
+		{"too small", models.TeamRules{MinMembers: 2}, aged(20), []string{"min_members"}},
+		{"too big", models.TeamRules{MaxMembers: 2}, aged(20, 21, 22), []string{"max_members"}},
+		{"minor present", models.TeamRules{AgeConstraints: []models.AgeConstraint{minor}}, aged(17, 30), []string{}},
+		{"turning 18 today is no longer a minor", models.TeamRules{AgeConstraints: []models.AgeConstraint{minor}}, aged(18, 30), []string{"age"}},
+		{"turning 18 today is an adult", models.TeamRules{AgeConstraints: []models.AgeConstraint{adults}}, aged(18, 30), []string{}},
+		{"not enough adults", models.TeamRules{AgeConstraints: []models.AgeConstraint{adults}}, aged(17, 30), []string{"age"}},
+		{
+			name:    "every violation is reported",
+			rules:   models.TeamRules{MinMembers: 3, AgeConstraints: []models.AgeConstraint{minor, adults}},
+			members: aged(30),
+			want:    []string{"min_members", "age", "age"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := rulesOf(teamrules.Check(&test.rules, test.members, now))
+			if !slices.Equal(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckJoin(t *testing.T) {
+	rules := models.TeamRules{
+		MaxMembers:     3,
+		AgeConstraints: []models.AgeConstraint{{MaxAge: 18, MinCount: 1}},
+	}
+
+	tests := []struct {
+		name    string
+		rules   models.TeamRules
+		members []models.User
+		want    []string
+	}{
+		{"no size limit", models.TeamRules{AgeConstraints: rules.AgeConstraints}, aged(30, 30, 30, 30), []string{}},
+		{"free places left", rules, aged(30, 30), []string{}},
+		{"last place keeps the composition", rules, aged(30, 30, 17), []string{}},
+		{"last place breaks the composition", rules, aged(30, 30, 30), []string{"age"}},
+		{"over the limit", rules, aged(17, 30, 30, 30), []string{"max_members"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := rulesOf(teamrules.CheckJoin(&test.rules, test.members, now))
+			if !slices.Equal(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckTeamCount(t *testing.T) {
+	tests := []struct {
+		name  string
+		max   int
+		count int64
+		want  []string
+	}{
+		{"unlimited", 0, 100, []string{}},
+		{"below the limit", 3, 2, []string{}},
+		{"at the limit", 3, 3, []string{"max_teams"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := rulesOf(teamrules.CheckTeamCount(&models.TeamRules{MaxTeams: test.max}, test.count))
+			if !slices.Equal(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
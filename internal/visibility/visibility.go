@@ -0,0 +1,376 @@
+package visibility
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/SomeSuperCoder/global-chat/internal"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Audiences a field can be opened to with the `visible:"..."` struct tag.
+// Fields without the tag are public, admins see everything.
+const (
+	Public    = "public"
+	Self      = "self"
+	Teammates = "teammates"
+	Judges    = "judges"
+	Admins    = "admins"
+)
+
+// Owner is implemented by models whose fields are visible depending on who they belong to
+type Owner interface {
+	VisibilityOwner() (user bson.ObjectID, team bson.ObjectID)
+}
+
+type owner struct {
+	user bson.ObjectID
+	team bson.ObjectID
+}
+
+var marshalerType = reflect.TypeFor[json.Marshaler]()
+var textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+var ownerType = reflect.TypeFor[Owner]()
+
+// Filter converts the value into a JSON-ready form without the fields the viewer may not see.
+// A nil viewer is an anonymous caller.
+func Filter(value any, viewer *models.User) any {
+	if value == nil {
+		return nil
+	}
+
+	return filter(reflect.ValueOf(value), viewer, nil)
+}
+
+func filter(v reflect.Value, viewer *models.User, current *owner) any {
+	// Types without restricted fields are encoded as is
+	if !isRestricted(v.Type()) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return filter(v.Elem(), viewer, current)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		values := make([]any, v.Len())
+		for i := range v.Len() {
+			values[i] = filter(v.Index(i), viewer, current)
+		}
+		return values
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		values := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			values[mapKey(iter.Key())] = filter(iter.Value(), viewer, current)
+		}
+		return values
+	case reflect.Struct:
+		return filterStruct(v, viewer, current)
+	}
+
+	return v.Interface()
+}
+
+func filterStruct(v reflect.Value, viewer *models.User, current *owner) any {
+	var fields []structField
+	collectFields(v, viewer, current, 0, &fields)
+
+	return dominantFields(fields)
+}
+
+// structField is a candidate member of the encoded object, hidden ones still take part in name resolution
+type structField struct {
+	member
+	depth  int
+	tagged bool
+	hidden bool
+}
+
+// collectFields walks the struct and flattens embedded structs the same way encoding/json does
+func collectFields(v reflect.Value, viewer *models.User, current *owner, depth int, fields *[]structField) {
+	if o, ok := ownerOf(v); ok {
+		current = o
+	}
+
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		// Values reached through unexported embedded structs are read-only for reflect, so they are skipped
+		if !field.IsExported() {
+			continue
+		}
+
+		name, tagged, omitEmpty, skip := jsonName(field)
+		if skip {
+			continue
+		}
+
+		visible := canSee(field.Tag.Get("visible"), viewer, current)
+		fieldValue := v.Field(i)
+
+		if isEmbeddedStruct(field, tagged) {
+			if !visible {
+				continue
+			}
+			if fieldValue.Kind() == reflect.Pointer {
+				if fieldValue.IsNil() {
+					continue
+				}
+				fieldValue = fieldValue.Elem()
+			}
+
+			collectFields(fieldValue, viewer, current, depth+1, fields)
+			continue
+		}
+
+		candidate := structField{
+			member: member{name: name},
+			depth:  depth,
+			tagged: tagged,
+			hidden: !visible || (omitEmpty && fieldValue.IsZero()),
+		}
+		if !candidate.hidden {
+			candidate.value = filter(fieldValue, viewer, current)
+		}
+
+		*fields = append(*fields, candidate)
+	}
+}
+
+// isEmbeddedStruct reports whether the field is an anonymous struct without a JSON name, which gets flattened
+func isEmbeddedStruct(field reflect.StructField, tagged bool) bool {
+	if !field.Anonymous || tagged {
+		return false
+	}
+
+	t := field.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct
+}
+
+// dominantFields resolves duplicate names: the shallowest field wins,
+// ties are broken by an explicit JSON name and otherwise all of them are dropped
+func dominantFields(fields []structField) object {
+	byName := make(map[string][]int, len(fields))
+	for i, field := range fields {
+		byName[field.name] = append(byName[field.name], i)
+	}
+
+	// The winner keeps its own position, like in encoding/json
+	result := make(object, 0, len(byName))
+	for i, field := range fields {
+		if field.hidden || dominantField(fields, byName[field.name]) != i {
+			continue
+		}
+		result = append(result, field.member)
+	}
+
+	return result
+}
+
+// dominantField returns the index of the field that gets encoded under the shared name or -1
+func dominantField(fields []structField, indexes []int) int {
+	depth := fields[indexes[0]].depth
+	for _, i := range indexes {
+		depth = min(depth, fields[i].depth)
+	}
+
+	var candidates []int
+	var tagged []int
+	for _, i := range indexes {
+		if fields[i].depth != depth {
+			continue
+		}
+		candidates = append(candidates, i)
+		if fields[i].tagged {
+			tagged = append(tagged, i)
+		}
+	}
+
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	if len(tagged) == 1 {
+		return tagged[0]
+	}
+
+	return -1
+}
+
+func canSee(tag string, viewer *models.User, current *owner) bool {
+	if tag == "" {
+		return true
+	}
+
+	for _, audience := range strings.Split(tag, ",") {
+		switch strings.TrimSpace(audience) {
+		case Public:
+			return true
+		case Self:
+			if viewer != nil && current != nil && viewer.ID == current.user {
+				return true
+			}
+		case Teammates:
+			if viewer != nil && current != nil && isTeammate(viewer, current) {
+				return true
+			}
+		case Judges:
+			if viewer != nil && viewer.Role == models.Judge {
+				return true
+			}
+		}
+	}
+
+	return viewer != nil && viewer.Role == models.Admin
+}
+
+func isTeammate(viewer *models.User, current *owner) bool {
+	if viewer.ID == current.user {
+		return true
+	}
+
+	return !current.team.IsZero() && current.team != internal.UndefinedObjectID && viewer.Team == current.team
+}
+
+func ownerOf(v reflect.Value) (*owner, bool) {
+	var value reflect.Value
+	if v.Type().Implements(ownerType) {
+		value = v
+	} else if reflect.PointerTo(v.Type()).Implements(ownerType) && v.CanAddr() {
+		value = v.Addr()
+	} else if reflect.PointerTo(v.Type()).Implements(ownerType) {
+		copied := reflect.New(v.Type())
+		copied.Elem().Set(v)
+		value = copied
+	} else {
+		return nil, false
+	}
+
+	user, team := value.Interface().(Owner).VisibilityOwner()
+	return &owner{user: user, team: team}, true
+}
+
+// jsonName returns the encoded name, whether it was set by the tag, omitempty and whether the field is skipped
+func jsonName(field reflect.StructField) (string, bool, bool, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false, true
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	tagged := name != ""
+	if !tagged {
+		name = field.Name
+	}
+
+	return name, tagged, strings.Contains(options, "omitempty"), false
+}
+
+func mapKey(key reflect.Value) string {
+	if key.Type().Implements(textMarshalerType) {
+		text, err := key.Interface().(encoding.TextMarshaler).MarshalText()
+		if err == nil {
+			return string(text)
+		}
+	}
+
+	return fmt.Sprint(key.Interface())
+}
+
+// ====================
+var restrictedCache sync.Map
+
+// isRestricted reports whether the type contains any field with a visibility tag
+func isRestricted(t reflect.Type) bool {
+	if cached, ok := restrictedCache.Load(t); ok {
+		return cached.(bool)
+	}
+
+	// Guard against recursive types while computing
+	restrictedCache.Store(t, false)
+	restricted := computeRestricted(t)
+	restrictedCache.Store(t, restricted)
+
+	return restricted
+}
+
+func computeRestricted(t reflect.Type) bool {
+	if t.Kind() != reflect.Interface && (t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType)) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		// The dynamic type is only known at runtime
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return isRestricted(t.Elem())
+	case reflect.Map:
+		return isRestricted(t.Elem())
+	case reflect.Struct:
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Tag.Get("visible") != "" || isRestricted(field.Type) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ====================
+type member struct {
+	name  string
+	value any
+}
+
+// object keeps the struct field order when encoded
+type object []member
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		key, err := json.Marshal(m.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
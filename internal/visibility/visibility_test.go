@@ -0,0 +1,230 @@
+package visibility_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/handlers"
+	"github.com/SomeSuperCoder/global-chat/internal/visibility"
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var (
+	teamID   = bson.NewObjectID()
+	owner    = models.User{ID: bson.NewObjectID(), Name: "Owner", Role: models.Participant, Team: teamID, Birthdate: time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC), ChatID: 42}
+	mate     = &models.User{ID: bson.NewObjectID(), Role: models.Participant, Team: teamID}
+	stranger = &models.User{ID: bson.NewObjectID(), Role: models.Participant, Team: bson.NewObjectID()}
+	judge    = &models.User{ID: bson.NewObjectID(), Role: models.Judge}
+	admin    = &models.User{ID: bson.NewObjectID(), Role: models.Admin}
+)
+
+// encode runs the value through the filter and decodes the JSON back into generic maps
+func encode(t *testing.T, value any, viewer *models.User) map[string]any {
+	t.Helper()
+
+	raw, err := json.Marshal(visibility.Filter(value, viewer))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("unmarshal %s: %v", raw, err)
+	}
+
+	return decoded
+}
+
+func checkKeys(t *testing.T, object map[string]any, visible []string, hidden []string) {
+	t.Helper()
+
+	for _, key := range visible {
+		if _, ok := object[key]; !ok {
+			t.Errorf("expected %q to be visible in %v", key, object)
+		}
+	}
+	for _, key := range hidden {
+		if _, ok := object[key]; ok {
+			t.Errorf("expected %q to be hidden in %v", key, object)
+		}
+	}
+}
+
+func TestFilterUser(t *testing.T) {
+	public := []string{"_id", "name", "role", "team", "username"}
+	private := []string{"birthdate", "chat_id"}
+
+	tests := []struct {
+		name    string
+		viewer  *models.User
+		visible []string
+		hidden  []string
+	}{
+		{"anonymous", nil, public, private},
+		{"self", &owner, append(public, private...), nil},
+		{"teammate", mate, public, private},
+		{"stranger", stranger, public, private},
+		{"judge", judge, public, private},
+		{"admin", admin, append(public, private...), nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Both the value and the pointer have to be filtered
+			checkKeys(t, encode(t, owner, test.viewer), test.visible, test.hidden)
+			checkKeys(t, encode(t, &owner, test.viewer), test.visible, test.hidden)
+		})
+	}
+}
+
+func TestFilterUsersResponse(t *testing.T) {
+	other := models.User{ID: bson.NewObjectID(), Team: bson.NewObjectID(), ChatID: 7}
+	response := handlers.UsersResponse{
+		Users:      []models.User{owner, other},
+		TotalCount: 2,
+	}
+
+	tests := []struct {
+		name   string
+		viewer *models.User
+		// Whether chat_id is visible for each user in the page
+		want []bool
+	}{
+		{"anonymous", nil, []bool{false, false}},
+		{"self", &owner, []bool{true, false}},
+		{"teammate", mate, []bool{false, false}},
+		{"judge", judge, []bool{false, false}},
+		{"admin", admin, []bool{true, true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			object := encode(t, response, test.viewer)
+			if object["count"] != float64(2) {
+				t.Errorf("count = %v, want 2", object["count"])
+			}
+
+			users := object["users"].([]any)
+			if len(users) != len(test.want) {
+				t.Fatalf("got %d users, want %d", len(users), len(test.want))
+			}
+			for i, user := range users {
+				_, ok := user.(map[string]any)["chat_id"]
+				if ok != test.want[i] {
+					t.Errorf("user %d: chat_id visible = %v, want %v", i, ok, test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFilterTeamsResponse(t *testing.T) {
+	response := handlers.TeamsResponse{
+		Teams: []models.Team{
+			{ID: teamID, Name: "Old", NameStatus: models.NameApproved, PendingName: "New"},
+		},
+		TotalCount: 1,
+	}
+
+	tests := []struct {
+		name    string
+		viewer  *models.User
+		visible bool
+	}{
+		{"anonymous", nil, false},
+		{"self", &owner, true},
+		{"teammate", mate, true},
+		{"stranger", stranger, false},
+		{"judge", judge, false},
+		{"admin", admin, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			object := encode(t, response, test.viewer)
+			team := object["teams"].([]any)[0].(map[string]any)
+			if team["name"] != "Old" {
+				t.Errorf("name = %v, want Old", team["name"])
+			}
+
+			_, ok := team["pending_name"]
+			if ok != test.visible {
+				t.Errorf("pending_name visible = %v, want %v", ok, test.visible)
+			}
+		})
+	}
+}
+
+type Base struct {
+	ID     string `json:"id"`
+	Secret string `json:"secret" visible:"admins"`
+	Shadow string `json:"shadow"`
+}
+
+type Extra struct {
+	Note   string `json:"note"`
+	Shadow string `json:"shadow"`
+	Clash  string `json:"clash"`
+}
+
+type Other struct {
+	Clash string `json:"clash"`
+}
+
+type Embedding struct {
+	Base
+	*Extra
+	Other
+	Named  Base   `json:"named"`
+	Shadow string `json:"shadow"`
+}
+
+func TestFilterEmbedded(t *testing.T) {
+	value := Embedding{
+		Base:   Base{ID: "base", Secret: "secret", Shadow: "base"},
+		Extra:  &Extra{Note: "note", Shadow: "extra", Clash: "extra"},
+		Other:  Other{Clash: "other"},
+		Named:  Base{ID: "named", Secret: "named secret"},
+		Shadow: "outer",
+	}
+
+	tests := []struct {
+		name    string
+		value   any
+		viewer  *models.User
+		visible []string
+		hidden  []string
+		// The outer field has to shadow the embedded ones
+		shadow string
+	}{
+		{"anonymous", value, nil, []string{"id", "note", "named", "shadow"}, []string{"secret", "Base", "Extra", "clash"}, "outer"},
+		{"admin", value, admin, []string{"id", "secret", "note", "named", "shadow"}, []string{"Base", "Extra", "clash"}, "outer"},
+		{"nil embedded pointer", Embedding{Base: Base{ID: "base"}}, nil, []string{"id"}, []string{"note"}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			object := encode(t, test.value, test.viewer)
+			checkKeys(t, object, test.visible, test.hidden)
+
+			if object["shadow"] != test.shadow {
+				t.Errorf("shadow = %v, want %q", object["shadow"], test.shadow)
+			}
+		})
+	}
+
+	// With every field visible the output has to match encoding/json
+	want, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(visibility.Filter(value, admin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,176 @@
+package voting
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"github.com/SomeSuperCoder/global-chat/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var ErrClosed = errors.New("Voting is closed")
+var ErrOwnTeam = errors.New("You can't vote for your own team")
+var ErrNotParticipant = errors.New("Only participants can vote")
+
+// Ballot is what a voter can still vote for
+type Ballot struct {
+	Open     bool         `json:"open"`
+	StartsAt time.Time    `json:"starts_at"`
+	EndsAt   time.Time    `json:"ends_at"`
+	Limit    int          `json:"limit"`
+	Left     int          `json:"left"`
+	Teams    []BallotTeam `json:"teams"`
+}
+
+type BallotTeam struct {
+	Team  bson.ObjectID `json:"team"`
+	Name  string        `json:"name"`
+	Own   bool          `json:"own"`
+	Voted bool          `json:"voted"`
+}
+
+// Votable reports whether the voter can still pick the team
+func (t *BallotTeam) Votable() bool {
+	return !t.Own && !t.Voted
+}
+
+func LoadBallot(ctx context.Context, settingsRepo *repository.SettingsRepo, teamRepo *repository.TeamRepo, voteRepo *repository.VoteRepo, voter *models.User) (*Ballot, error) {
+	settings, err := settingsRepo.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := teamRepo.Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	votes, err := voteRepo.FindByVoter(ctx, voter.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	voted := make(map[bson.ObjectID]bool, len(votes))
+	for _, vote := range votes {
+		voted[vote.Team] = true
+	}
+
+	ballot := &Ballot{
+		Open:     settings.VotingOpen(time.Now()),
+		StartsAt: settings.VotingStart,
+		EndsAt:   settings.VotingEnd,
+		Limit:    settings.VoteLimit(),
+		Left:     max(settings.VoteLimit()-len(votes), 0),
+		Teams:    make([]BallotTeam, 0, len(teams)),
+	}
+	for _, team := range teams {
+		ballot.Teams = append(ballot.Teams, BallotTeam{
+			Team:  team.ID,
+			Name:  team.NameFor(voter),
+			Own:   team.ID == voter.Team,
+			Voted: voted[team.ID],
+		})
+	}
+
+	return ballot, nil
+}
+
+// Cast records the vote of the participant for the team if voting is open and they have votes left
+func Cast(ctx context.Context, settingsRepo *repository.SettingsRepo, teamRepo *repository.TeamRepo, voteRepo *repository.VoteRepo, voter *models.User, teamID bson.ObjectID) (*models.Vote, *models.Team, error) {
+	if voter.Role != models.Participant {
+		return nil, nil, ErrNotParticipant
+	}
+
+	settings, err := settingsRepo.Get(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	if !settings.VotingOpen(now) {
+		return nil, nil, ErrClosed
+	}
+
+	team, err := teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if team.ID == voter.Team {
+		return nil, nil, ErrOwnTeam
+	}
+
+	vote, err := voteRepo.Cast(ctx, &models.Vote{
+		Voter:     voter.ID,
+		Team:      team.ID,
+		CreatedAt: now,
+	}, settings.VoteLimit())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return vote, team, nil
+}
+
+// Result is the number of votes a team got
+type Result struct {
+	Team  bson.ObjectID `json:"team"`
+	Name  string        `json:"name"`
+	Votes int           `json:"votes"`
+	Rank  int           `json:"rank"`
+}
+
+// Results can be checked against the audit log, Digest is the same for both
+type Results struct {
+	Final  bool     `json:"final"`
+	Total  int      `json:"total"`
+	Digest string   `json:"digest"`
+	Teams  []Result `json:"teams"`
+}
+
+// Tally counts the votes of every team, teams with the same number of votes share the rank.
+// The team names are the ones the viewer may see.
+func Tally(teams []models.Team, votes []models.Vote, viewer *models.User) Results {
+	counts := make(map[bson.ObjectID]int, len(teams))
+	for _, vote := range votes {
+		counts[vote.Team]++
+	}
+
+	results := Results{
+		Total:  len(votes),
+		Digest: Digest(votes),
+		Teams:  make([]Result, 0, len(teams)),
+	}
+	for _, team := range teams {
+		results.Teams = append(results.Teams, Result{
+			Team:  team.ID,
+			Name:  team.NameFor(viewer),
+			Votes: counts[team.ID],
+		})
+	}
+
+	sort.SliceStable(results.Teams, func(i, j int) bool {
+		return results.Teams[i].Votes > results.Teams[j].Votes
+	})
+	for i := range results.Teams {
+		if i > 0 && results.Teams[i].Votes == results.Teams[i-1].Votes {
+			results.Teams[i].Rank = results.Teams[i-1].Rank
+		} else {
+			results.Teams[i].Rank = i + 1
+		}
+	}
+
+	return results
+}
+
+// Digest is a SHA-256 hash over every vote in the order they were cast, any change to the votes changes it
+func Digest(votes []models.Vote) string {
+	hash := sha256.New()
+	for _, vote := range votes {
+		fmt.Fprintf(hash, "%s,%s,%s,%d,%d\n", vote.ID.Hex(), vote.Voter.Hex(), vote.Team.Hex(), vote.Slot, vote.CreatedAt.UnixMilli())
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Push is the provider independent part of a push event
+type Push struct {
+	Provider string
+	Ref      string
+	// Every address of the repository the provider sent
+	RepoURLs []string
+	Commits  []PushCommit
+	// Number of pushed commits, the providers list only the first ones in Commits
+	Size int
+	// Page comparing the pushed range, to look up the unlisted commits
+	CompareURL string
+}
+
+// Unlisted is the number of pushed commits the payload left out
+func (p *Push) Unlisted() int {
+	return max(p.Size-len(p.Commits), 0)
+}
+
+type PushCommit struct {
+	SHA       string
+	Message   string
+	URL       string
+	Author    string
+	Email     string
+	Timestamp time.Time
+}
+
+type pushAuthor struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type pushCommit struct {
+	ID        string     `json:"id"`
+	Message   string     `json:"message"`
+	URL       string     `json:"url"`
+	Timestamp time.Time  `json:"timestamp"`
+	Author    pushAuthor `json:"author"`
+}
+
+func ParseGitHubPush(body []byte) (*Push, error) {
+	var event struct {
+		Ref        string `json:"ref"`
+		Repository struct {
+			HTMLURL  string `json:"html_url"`
+			CloneURL string `json:"clone_url"`
+			SSHURL   string `json:"ssh_url"`
+		} `json:"repository"`
+		Commits []pushCommit `json:"commits"`
+		Compare string       `json:"compare"`
+		// Only the distinct commits are new, the others were already pushed to another branch
+		Size         int  `json:"size"`
+		DistinctSize *int `json:"distinct_size"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+
+	size := event.Size
+	if event.DistinctSize != nil {
+		size = *event.DistinctSize
+	}
+
+	return &Push{
+		Provider:   GitHub,
+		Ref:        event.Ref,
+		RepoURLs:   []string{event.Repository.HTMLURL, event.Repository.CloneURL, event.Repository.SSHURL},
+		Commits:    convertCommits(event.Commits),
+		Size:       max(size, len(event.Commits)),
+		CompareURL: event.Compare,
+	}, nil
+}
+
+func ParseGitLabPush(body []byte) (*Push, error) {
+	var event struct {
+		Ref     string `json:"ref"`
+		Project struct {
+			WebURL     string `json:"web_url"`
+			GitHTTPURL string `json:"git_http_url"`
+			GitSSHURL  string `json:"git_ssh_url"`
+		} `json:"project"`
+		Commits           []pushCommit `json:"commits"`
+		TotalCommitsCount int          `json:"total_commits_count"`
+		Before            string       `json:"before"`
+		After             string       `json:"after"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+
+	compare := ""
+	if event.Project.WebURL != "" && event.Before != "" && event.After != "" {
+		compare = event.Project.WebURL + "/-/compare/" + event.Before + "..." + event.After
+	}
+
+	return &Push{
+		Provider:   GitLab,
+		Ref:        event.Ref,
+		RepoURLs:   []string{event.Project.WebURL, event.Project.GitHTTPURL, event.Project.GitSSHURL},
+		Commits:    convertCommits(event.Commits),
+		Size:       max(event.TotalCommitsCount, len(event.Commits)),
+		CompareURL: compare,
+	}, nil
+}
+
+func convertCommits(commits []pushCommit) []PushCommit {
+	result := make([]PushCommit, 0, len(commits))
+	for _, commit := range commits {
+		result = append(result, PushCommit{
+			SHA:       commit.ID,
+			Message:   commit.Message,
+			URL:       commit.URL,
+			Author:    commit.Author.Name,
+			Email:     commit.Author.Email,
+			Timestamp: commit.Timestamp,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,92 @@
+package webhook_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/SomeSuperCoder/global-chat/internal/webhook"
+)
+
+func TestParseGitHubPush(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		size     int
+		unlisted int
+	}{
+		{
+			name: "distinct commits",
+			body: `{
+				"ref": "refs/heads/main",
+				"repository": {"html_url": "https://github.com/org/repo", "clone_url": "https://github.com/org/repo.git", "ssh_url": "git@github.com:org/repo.git"},
+				"compare": "https://github.com/org/repo/compare/a...b",
+				"size": 25, "distinct_size": 22,
+				"commits": [{"id": "abc", "message": "Fix", "url": "https://github.com/org/repo/commit/abc", "timestamp": "2024-05-01T10:00:00Z", "author": {"name": "Ann", "email": "ann@example.com"}}]
+			}`,
+			size:     22,
+			unlisted: 21,
+		},
+		{
+			name:     "no size falls back to the listed commits",
+			body:     `{"ref": "refs/heads/main", "commits": [{"id": "a"}, {"id": "b"}]}`,
+			size:     2,
+			unlisted: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			push, err := webhook.ParseGitHubPush([]byte(test.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if push.Provider != webhook.GitHub || push.Ref != "refs/heads/main" {
+				t.Errorf("provider %q, ref %q", push.Provider, push.Ref)
+			}
+			if push.Size != test.size || push.Unlisted() != test.unlisted {
+				t.Errorf("size %d, unlisted %d, want %d and %d", push.Size, push.Unlisted(), test.size, test.unlisted)
+			}
+		})
+	}
+
+	push, _ := webhook.ParseGitHubPush([]byte(tests[0].body))
+	commit := push.Commits[0]
+	if commit.SHA != "abc" || commit.Author != "Ann" || commit.Email != "ann@example.com" || commit.Timestamp.IsZero() {
+		t.Errorf("commit parsed as %+v", commit)
+	}
+	if !slices.Contains(push.RepoURLs, "git@github.com:org/repo.git") || push.CompareURL == "" {
+		t.Errorf("repository URLs %v, compare %q", push.RepoURLs, push.CompareURL)
+	}
+}
+
+func TestParseGitLabPush(t *testing.T) {
+	push, err := webhook.ParseGitLabPush([]byte(`{
+		"ref": "refs/heads/main",
+		"before": "a1", "after": "b2",
+		"project": {"web_url": "https://gitlab.com/group/repo", "git_http_url": "https://gitlab.com/group/repo.git", "git_ssh_url": "git@gitlab.com:group/repo.git"},
+		"total_commits_count": 30,
+		"commits": [{"id": "b2", "message": "Add", "author": {"name": "Bob", "email": "bob@example.com"}}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if push.Provider != webhook.GitLab || push.Size != 30 || push.Unlisted() != 29 {
+		t.Errorf("provider %q, size %d, unlisted %d", push.Provider, push.Size, push.Unlisted())
+	}
+	if want := "https://gitlab.com/group/repo/-/compare/a1...b2"; push.CompareURL != want {
+		t.Errorf("compare %q, want %q", push.CompareURL, want)
+	}
+	if len(push.Commits) != 1 || push.Commits[0].Author != "Bob" {
+		t.Errorf("commits parsed as %+v", push.Commits)
+	}
+}
+
+func TestParseInvalidPush(t *testing.T) {
+	if _, err := webhook.ParseGitHubPush([]byte("{")); err == nil {
+		t.Error("expected an error for a broken GitHub payload")
+	}
+	if _, err := webhook.ParseGitLabPush([]byte("[]")); err == nil {
+		t.Error("expected an error for a broken GitLab payload")
+	}
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	GitHub = "github"
+	GitLab = "gitlab"
+)
+
+var ErrNoSecret = errors.New("Webhook secret is not configured")
+var ErrInvalidSignature = errors.New("Invalid webhook signature")
+
+// Secret returns the shared secret configured for the provider
+func Secret(provider string) ([]byte, error) {
+	secret := os.Getenv(strings.ToUpper(provider) + "_WEBHOOK_SECRET")
+	if secret == "" {
+		return nil, ErrNoSecret
+	}
+
+	return []byte(secret), nil
+}
+
+// VerifyGitHub checks the X-Hub-Signature-256 header: "sha256=" + hex HMAC of the raw body
+func VerifyGitHub(body []byte, header string, secret []byte) error {
+	sig, found := strings.CutPrefix(header, "sha256=")
+	if !found {
+		return ErrInvalidSignature
+	}
+
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// VerifyGitLab checks the X-Gitlab-Token header, GitLab sends the secret as is
+func VerifyGitLab(header string, secret []byte) error {
+	if subtle.ConstantTimeCompare([]byte(header), secret) != 1 {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// NormalizeRepoURL reduces the different forms of a repository address to host/path,
+// e.g. "git@github.com:Org/Repo.git" and "https://www.github.com/org/repo/tree/main" become "github.com/org/repo"
+func NormalizeRepoURL(raw string) string {
+	value := strings.ToLower(strings.TrimSpace(raw))
+
+	// SCP-like SSH addresses
+	if rest, found := strings.CutPrefix(value, "git@"); found && !strings.Contains(value, "://") {
+		value = "ssh://" + strings.Replace(rest, ":", "/", 1)
+	}
+	if !strings.Contains(value, "://") {
+		value = "https://" + value
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return value
+	}
+
+	host := strings.TrimPrefix(parsed.Hostname(), "www.")
+	path := strings.Trim(parsed.Path, "/")
+
+	// Drop pages inside the repository
+	if before, _, found := strings.Cut(path, "/-/"); found {
+		path = before
+	}
+	if host == "github.com" {
+		if segments := strings.Split(path, "/"); len(segments) > 2 {
+			path = strings.Join(segments[:2], "/")
+		}
+	}
+	path = strings.TrimSuffix(path, ".git")
+
+	return host + "/" + path
+}
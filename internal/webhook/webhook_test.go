@@ -0,0 +1,103 @@
+package webhook_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SomeSuperCoder/global-chat/internal/webhook"
+)
+
+func TestVerifyGitHub(t *testing.T) {
+	// The example from the GitHub webhook documentation
+	secret := []byte("It's a Secret to Everybody")
+	body := []byte("Hello, World!")
+	valid := "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+
+	tests := []struct {
+		name   string
+		body   []byte
+		header string
+		secret []byte
+		valid  bool
+	}{
+		{"known vector", body, valid, secret, true},
+		{"other body", []byte("Hello, World?"), valid, secret, false},
+		{"other secret", body, valid, []byte("It's a Secret to Nobody"), false},
+		{"missing prefix", body, valid[len("sha256="):], secret, false},
+		{"sha1 prefix", body, "sha1=" + valid[len("sha256="):], secret, false},
+		{"not hex", body, "sha256=zz7107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17", secret, false},
+		{"truncated", body, valid[:len(valid)-2], secret, false},
+		{"empty digest", body, "sha256=", secret, false},
+		{"empty header", body, "", secret, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := webhook.VerifyGitHub(test.body, test.header, test.secret)
+			if test.valid && err != nil {
+				t.Errorf("expected a valid signature, got %v", err)
+			}
+			if !test.valid && !errors.Is(err, webhook.ErrInvalidSignature) {
+				t.Errorf("expected ErrInvalidSignature, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyGitLab(t *testing.T) {
+	secret := []byte("token")
+
+	tests := []struct {
+		name   string
+		header string
+		valid  bool
+	}{
+		{"same token", "token", true},
+		{"other token", "tokem", false},
+		{"prefix", "tok", false},
+		{"longer", "token2", false},
+		{"empty", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := webhook.VerifyGitLab(test.header, secret)
+			if (err == nil) != test.valid {
+				t.Errorf("err = %v, valid %v", err, test.valid)
+			}
+		})
+	}
+}
+
+func TestNormalizeRepoURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"https://github.com/org/repo", "github.com/org/repo"},
+		{"https://github.com/Org/Repo.git", "github.com/org/repo"},
+		{"https://www.github.com/org/repo/", "github.com/org/repo"},
+		{"https://github.com/org/repo/tree/main", "github.com/org/repo"},
+		{"https://github.com/org/repo/blob/main/README.md", "github.com/org/repo"},
+		{"git@github.com:Org/Repo.git", "github.com/org/repo"},
+		{"ssh://git@github.com/org/repo.git", "github.com/org/repo"},
+		{"github.com/org/repo", "github.com/org/repo"},
+		{"  https://github.com/org/repo  ", "github.com/org/repo"},
+		{"https://gitlab.com/group/subgroup/repo.git", "gitlab.com/group/subgroup/repo"},
+		{"https://gitlab.com/group/subgroup/repo/-/tree/main", "gitlab.com/group/subgroup/repo"},
+		{"git@gitlab.com:group/repo.git", "gitlab.com/group/repo"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.raw, func(t *testing.T) {
+			if got := webhook.NormalizeRepoURL(test.raw); got != test.want {
+				t.Errorf("NormalizeRepoURL(%q) = %q, want %q", test.raw, got, test.want)
+			}
+		})
+	}
+
+	// Different repositories must stay apart
+	if webhook.NormalizeRepoURL("https://github.com/org/repo") == webhook.NormalizeRepoURL("https://github.com/org/repo2") {
+		t.Error("different repositories got the same key")
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Assignment lets a judge grade a team
+type Assignment struct {
+	ID        bson.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Judge     bson.ObjectID `bson:"judge" json:"judge"`
+	Team      bson.ObjectID `bson:"team" json:"team"`
+	CreatedAt time.Time     `bson:"created_at" json:"created_at"`
+	// Generation tags the assignments stored by one generation run
+	Generation bson.ObjectID `bson:"generation,omitempty" json:"-"`
+}
+
+// Conflict is a declared conflict of interest of a judge with a team or with a participant,
+// in which case it applies to whatever team the participant is in
+type Conflict struct {
+	ID        bson.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Judge     bson.ObjectID `bson:"judge" json:"judge"`
+	Team      bson.ObjectID `bson:"team,omitempty" json:"team,omitempty"`
+	User      bson.ObjectID `bson:"user,omitempty" json:"user,omitempty"`
+	Reason    string        `bson:"reason" json:"reason" visible:"admins"`
+	CreatedAt time.Time     `bson:"created_at" json:"created_at"`
+}
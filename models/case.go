@@ -7,4 +7,12 @@ type Case struct {
 	Name        string        `bson:"name" json:"name"`
 	Description string        `bson:"description" json:"description"`
 	ImageURI    string        `bson:"image_uri" json:"image_uri"`
+	// Maximum number of teams working on the case, 0 means unlimited
+	Capacity int `bson:"capacity" json:"capacity"`
+	// Judges of the case, e.g. experts of the sponsor, score every team working on it
+	Judges []bson.ObjectID `bson:"judges" json:"judges"`
+}
+
+func (c *Case) HasJudge(judge bson.ObjectID) bool {
+	return containsID(c.Judges, judge)
 }
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Commit is a commit reported by a GitHub or GitLab push webhook
+type Commit struct {
+	ID       bson.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Team     bson.ObjectID `bson:"team" json:"team"`
+	Provider string        `bson:"provider" json:"provider"`
+	Repo     string        `bson:"repo" json:"repo"`
+	Ref      string        `bson:"ref" json:"ref"`
+	SHA      string        `bson:"sha" json:"sha"`
+	Message  string        `bson:"message" json:"message"`
+	URL      string        `bson:"url" json:"url"`
+	Author   string        `bson:"author" json:"author"`
+	Email    string        `bson:"email" json:"email"`
+	// Timestamp is the commit date set by the author, ReceivedAt is when it was pushed
+	Timestamp  time.Time `bson:"timestamp" json:"timestamp"`
+	ReceivedAt time.Time `bson:"received_at" json:"received_at"`
+}
+
+// TruncatedPush remembers a push whose payload listed only part of the commits
+type TruncatedPush struct {
+	ID         bson.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Team       bson.ObjectID `bson:"team" json:"team"`
+	Repo       string        `bson:"repo" json:"repo"`
+	Ref        string        `bson:"ref" json:"ref"`
+	Unlisted   int           `bson:"unlisted" json:"unlisted"`
+	CompareURL string        `bson:"compare_url" json:"compare_url"`
+	ReceivedAt time.Time     `bson:"received_at" json:"received_at"`
+}
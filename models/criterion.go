@@ -2,7 +2,54 @@ package models
 
 import "go.mongodb.org/mongo-driver/v2/bson"
 
+// DefaultMaxScore is the upper bound of criteria created before ranges were configurable
+const DefaultMaxScore = 10
+
 type Criterion struct {
-	ID   bson.ObjectID `bson:"_id,omitempty" json:"_id"`
-	Text string        `bson:"text" json:"text"`
+	ID          bson.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Text        string        `bson:"text" json:"text"`
+	Description string        `bson:"description" json:"description"`
+	MinScore    int           `bson:"min_score" json:"min_score"`
+	MaxScore    int           `bson:"max_score" json:"max_score"`
+	Weight      float64       `bson:"weight" json:"weight"`
+	// Criteria are listed by Order and may be grouped into categories
+	Order    int    `bson:"order" json:"order"`
+	Category string `bson:"category" json:"category"`
+	// Criteria of a case only apply to the teams working on it, zero means every team
+	Case bson.ObjectID `bson:"case,omitempty" json:"case,omitempty"`
+}
+
+// ScoreRange returns the accepted grades, legacy criteria without a range use 0..DefaultMaxScore
+func (c *Criterion) ScoreRange() (int, int) {
+	if c.MinScore == 0 && c.MaxScore == 0 {
+		return 0, DefaultMaxScore
+	}
+
+	return c.MinScore, c.MaxScore
+}
+
+// EffectiveWeight treats a missing weight as 1
+func (c *Criterion) EffectiveWeight() float64 {
+	if c.Weight <= 0 {
+		return 1
+	}
+
+	return c.Weight
+}
+
+// AppliesTo reports whether teams working on the case are scored on the criterion
+func (c *Criterion) AppliesTo(caseID bson.ObjectID) bool {
+	return c.Case.IsZero() || c.Case == caseID
+}
+
+// ApplicableCriteria keeps the global criteria and the criteria of the case, the zero case keeps only global ones
+func ApplicableCriteria(criteria []Criterion, caseID bson.ObjectID) []Criterion {
+	applicable := make([]Criterion, 0, len(criteria))
+	for _, criterion := range criteria {
+		if criterion.AppliesTo(caseID) {
+			applicable = append(applicable, criterion)
+		}
+	}
+
+	return applicable
 }
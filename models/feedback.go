@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Feedback is the overall written review of a judge for a team, the comments on
+// single criteria are kept with the scores
+type Feedback struct {
+	ID    bson.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Judge bson.ObjectID `bson:"judge" json:"judge" visible:"self"`
+	Team  bson.ObjectID `bson:"team" json:"team"`
+	// Zero outside of multi-round judging
+	Round bson.ObjectID `bson:"round,omitempty" json:"round,omitempty"`
+	Text  string        `bson:"text" json:"text"`
+	// Teams only see who wrote the feedback and the comments of the judge if it is signed
+	Signed    bool      `bson:"signed" json:"signed"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+func (f Feedback) VisibilityOwner() (bson.ObjectID, bson.ObjectID) {
+	return f.Judge, f.Team
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type ImpersonationGrant struct {
+	ID        bson.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Admin     bson.ObjectID `bson:"admin" json:"admin"`
+	Target    bson.ObjectID `bson:"target" json:"target"`
+	Reason    string        `bson:"reason" json:"reason"`
+	CreatedAt time.Time     `bson:"created_at" json:"created_at"`
+	ExpiresAt time.Time     `bson:"expires_at" json:"expires_at"`
+	Revoked   bool          `bson:"revoked" json:"revoked"`
+}
+
+type AuditEntry struct {
+	ID bson.ObjectID `bson:"_id,omitempty" json:"_id"`
+	// Actor is the admin, Subject is the user whose identity was used
+	Actor     bson.ObjectID `bson:"actor" json:"actor"`
+	Subject   bson.ObjectID `bson:"subject" json:"subject"`
+	Grant     bson.ObjectID `bson:"grant" json:"grant"`
+	Action    string        `bson:"action" json:"action"`
+	Status    int           `bson:"status" json:"status"`
+	CreatedAt time.Time     `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// SeekerProfile is published by a participant who is looking for a team
+type SeekerProfile struct {
+	ID            bson.ObjectID `bson:"_id,omitempty" json:"_id"`
+	User          bson.ObjectID `bson:"user" json:"user"`
+	Skills        []string      `bson:"skills" json:"skills"`
+	PreferredCase bson.ObjectID `bson:"preferred_case,omitempty" json:"preferred_case"`
+	Role          string        `bson:"role" json:"role"`
+	About         string        `bson:"about" json:"about"`
+	UpdatedAt     time.Time     `bson:"updated_at" json:"updated_at"`
+}
+
+// OpenPosition is published by a team looking for a member
+type OpenPosition struct {
+	ID          bson.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Team        bson.ObjectID `bson:"team" json:"team"`
+	Role        string        `bson:"role" json:"role"`
+	Skills      []string      `bson:"skills" json:"skills"`
+	Description string        `bson:"description" json:"description"`
+	CreatedAt   time.Time     `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Round is a judging stage with its own criteria, judges and time window
+type Round struct {
+	ID    bson.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Name  string        `bson:"name" json:"name"`
+	Order int           `bson:"order" json:"order"`
+	// Empty Judges means the judges assigned to the teams, empty Teams means every team
+	Criteria []bson.ObjectID `bson:"criteria" json:"criteria"`
+	Judges   []bson.ObjectID `bson:"judges" json:"judges"`
+	Teams    []bson.ObjectID `bson:"teams" json:"teams"`
+	// Scores are accepted between StartsAt and EndsAt, zero values leave the window open
+	StartsAt time.Time `bson:"starts_at" json:"starts_at"`
+	EndsAt   time.Time `bson:"ends_at" json:"ends_at"`
+}
+
+// IsOpen reports whether scores can be submitted at the moment
+func (r *Round) IsOpen(now time.Time) bool {
+	return (r.StartsAt.IsZero() || !now.Before(r.StartsAt)) && (r.EndsAt.IsZero() || now.Before(r.EndsAt))
+}
+
+func (r *Round) HasTeam(team bson.ObjectID) bool {
+	return len(r.Teams) == 0 || containsID(r.Teams, team)
+}
+
+func (r *Round) HasCriterion(criterion bson.ObjectID) bool {
+	return containsID(r.Criteria, criterion)
+}
+
+func containsID(ids []bson.ObjectID, id bson.ObjectID) bool {
+	for _, value := range ids {
+		if value == id {
+			return true
+		}
+	}
+
+	return false
+}
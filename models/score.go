@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Score is the grade of a judge for a team on one criterion
+type Score struct {
+	ID        bson.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Judge     bson.ObjectID `bson:"judge" json:"judge" visible:"self"`
+	Team      bson.ObjectID `bson:"team" json:"team"`
+	Criterion bson.ObjectID `bson:"criterion" json:"criterion"`
+	// Zero outside of multi-round judging
+	Round     bson.ObjectID `bson:"round,omitempty" json:"round,omitempty"`
+	Value     int           `bson:"value" json:"value"`
+	Comment   string        `bson:"comment" json:"comment"`
+	CreatedAt time.Time     `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time     `bson:"updated_at" json:"updated_at"`
+	// Every value the judge has set, oldest first
+	History []ScoreRevision `bson:"history" json:"history" visible:"admins"`
+}
+
+type ScoreRevision struct {
+	Value     int       `bson:"value" json:"value"`
+	Comment   string    `bson:"comment" json:"comment"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+func (s Score) VisibilityOwner() (bson.ObjectID, bson.ObjectID) {
+	return s.Judge, s.Team
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type Session struct {
+	ID          bson.ObjectID `bson:"_id,omitempty" json:"_id"`
+	User        bson.ObjectID `bson:"user" json:"user"`
+	RefreshHash string        `bson:"refresh_hash" json:"-"`
+	CreatedAt   time.Time     `bson:"created_at" json:"created_at"`
+	ExpiresAt   time.Time     `bson:"expires_at" json:"expires_at"`
+}
+
+// Revocation marks the moment after which previously issued access tokens of a user are stale
+type Revocation struct {
+	User      bson.ObjectID `bson:"_id" json:"user"`
+	RevokedAt time.Time     `bson:"revoked_at" json:"revoked_at"`
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Settings is a single document holding the hackathon wide configuration
+type Settings struct {
+	ID        string    `bson:"_id" json:"-"`
+	TeamRules TeamRules `bson:"team_rules" json:"team_rules"`
+	// Team names containing these words are rejected, new and renamed teams need admin approval if enabled
+	BannedWords      []string `bson:"banned_words" json:"banned_words" visible:"admins"`
+	TeamNameApproval bool     `bson:"team_name_approval" json:"team_name_approval"`
+	// Judges can change scores while judging is open, participants see the results once they are published
+	JudgingState JudgingState `bson:"judging_state" json:"judging_state"`
+	// How grades are normalized before ranking, see scoring.Methods. Locked while the results are published.
+	NormalizationMethod string `bson:"normalization_method" json:"normalization_method"`
+	// Applied in order when teams have the same total, see scoring.ParseTieBreaks
+	TieBreaks []string `bson:"tie_breaks" json:"tie_breaks"`
+	// Every user can cast VotesPerUser people's choice votes between VotingStart and VotingEnd
+	VotingStart  time.Time `bson:"voting_start" json:"voting_start"`
+	VotingEnd    time.Time `bson:"voting_end" json:"voting_end"`
+	VotesPerUser int       `bson:"votes_per_user" json:"votes_per_user"`
+	// Commits dated before the start are flagged in the team activity
+	HackathonStart time.Time `bson:"hackathon_start" json:"hackathon_start"`
+	// After the deadline only admins can change the case of a team
+	CaseSelectionDeadline time.Time `bson:"case_selection_deadline" json:"case_selection_deadline"`
+	// Submissions are flagged late after the deadline and locked once the grace period is over
+	SubmissionDeadline     time.Time `bson:"submission_deadline" json:"submission_deadline"`
+	LateSubmissionGraceMin int       `bson:"late_submission_grace_min" json:"late_submission_grace_min"`
+}
+
+// ResultsPublished reports whether participants can see the leaderboard and their scores
+func (s *Settings) ResultsPublished() bool {
+	return s.JudgingState == JudgingPublished
+}
+
+// VotingOpen reports whether votes are accepted, voting without a start is disabled
+func (s *Settings) VotingOpen(now time.Time) bool {
+	return !s.VotingStart.IsZero() && !now.Before(s.VotingStart) && (s.VotingEnd.IsZero() || now.Before(s.VotingEnd))
+}
+
+// VotingFinished reports whether the voting results are final
+func (s *Settings) VotingFinished(now time.Time) bool {
+	return !s.VotingEnd.IsZero() && !now.Before(s.VotingEnd)
+}
+
+// VoteLimit treats a missing number of votes as one vote per user
+func (s *Settings) VoteLimit() int {
+	if s.VotesPerUser <= 0 {
+		return 1
+	}
+
+	return s.VotesPerUser
+}
+
+// SubmissionLock is the moment after which submissions are frozen, zero if there is no deadline
+func (s *Settings) SubmissionLock() time.Time {
+	if s.SubmissionDeadline.IsZero() {
+		return time.Time{}
+	}
+
+	return s.SubmissionDeadline.Add(time.Duration(s.LateSubmissionGraceMin) * time.Minute)
+}
+
+// JudgingState moves from open to frozen to published, admins can also step back one state
+type JudgingState string
+
+const (
+	JudgingOpen      JudgingState = "open"
+	JudgingFrozen    JudgingState = "frozen"
+	JudgingPublished JudgingState = "published"
+)
+
+var judgingTransitions = map[JudgingState][]JudgingState{
+	JudgingOpen:      {JudgingFrozen},
+	JudgingFrozen:    {JudgingOpen, JudgingPublished},
+	JudgingPublished: {JudgingFrozen},
+}
+
+// CanMoveTo reports whether judging can go from the state straight to the next one
+func (s JudgingState) CanMoveTo(next JudgingState) bool {
+	return slices.Contains(judgingTransitions[s], next)
+}
+
+// TeamRules limit team size and composition. Zero values mean "no limit".
+type TeamRules struct {
+	MinMembers     int             `bson:"min_members" json:"min_members" validate:"min=0"`
+	MaxMembers     int             `bson:"max_members" json:"max_members" validate:"min=0"`
+	MaxTeams       int             `bson:"max_teams" json:"max_teams" validate:"min=0"`
+	AgeConstraints []AgeConstraint `bson:"age_constraints" json:"age_constraints" validate:"dive"`
+}
+
+// AgeConstraint requires at least MinCount members aged MinAge <= age < MaxAge,
+// e.g. {MaxAge: 18, MinCount: 1} is "at least one member under 18"
+type AgeConstraint struct {
+	MinAge   int `bson:"min_age" json:"min_age" validate:"min=0"`
+	MaxAge   int `bson:"max_age" json:"max_age" validate:"min=0"`
+	MinCount int `bson:"min_count" json:"min_count" validate:"min=1"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Submission is an immutable snapshot of a team's project
+type Submission struct {
+	ID              bson.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Team            bson.ObjectID `bson:"team" json:"team"`
+	Version         int           `bson:"version" json:"version"`
+	Repos           []string      `bson:"repos" json:"repos"`
+	PresentationURI string        `bson:"presentation_uri" json:"presentation_uri"`
+	DemoVideoURI    string        `bson:"demo_video_uri" json:"demo_video_uri"`
+	Description     string        `bson:"description" json:"description"`
+	Case            bson.ObjectID `bson:"case,omitempty" json:"case"`
+	SubmittedBy     bson.ObjectID `bson:"submitted_by" json:"submitted_by"`
+	CreatedAt       time.Time     `bson:"created_at" json:"created_at"`
+	// Submitted after the deadline but within the grace period
+	Late bool `bson:"late" json:"late"`
+	// The version the judges evaluate, marked by an admin or when submissions lock
+	Final bool `bson:"final" json:"final"`
+}
@@ -1,16 +1,66 @@
 package models
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
-type Grades map[bson.ObjectID]map[bson.ObjectID]uint16
+type NameStatus string
+
+const (
+	NameApproved NameStatus = "approved"
+	NamePending  NameStatus = "pending"
+	NameRejected NameStatus = "rejected"
+)
 
 type Team struct {
-	ID              bson.ObjectID `bson:"_id,omitempty" json:"_id"`
-	Name            string        `bson:"name" json:"name"`
+	ID      bson.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Name    string        `bson:"name" json:"name"`
+	NameKey string        `bson:"name_key,omitempty" json:"-"`
+	// Status of Name, unapproved teams are hidden from the public list
+	NameStatus NameStatus `bson:"name_status,omitempty" json:"name_status"`
+	// A rename of an approved team waiting for review, the old name stays public meanwhile
+	PendingName     string        `bson:"pending_name,omitempty" json:"pending_name,omitempty" visible:"teammates,admins"`
+	PendingNameKey  string        `bson:"pending_name_key,omitempty" json:"-"`
 	Leader          bson.ObjectID `bson:"leader" json:"leader"`
 	Repos           []string      `bson:"repos" json:"repos"`
 	PresentationURI string        `bson:"presentation_uri" json:"presentation_uri"`
-	Grades          Grades        `bson:"grades" json:"grades"`
+	Case            bson.ObjectID `bson:"case,omitempty" json:"case"`
+	// Set once the team got its results, publishing again after a freeze doesn't resend them
+	ResultsNotifiedAt time.Time `bson:"results_notified_at,omitempty" json:"-"`
+}
+
+// TeamNameKey reserves a name key for the team holding it as its name or pending rename
+type TeamNameKey struct {
+	Key  string        `bson:"_id" json:"key"`
+	Team bson.ObjectID `bson:"team" json:"team"`
+}
+
+// NameApproved reports whether the name passed moderation, names from before moderation have no status
+func (t *Team) NameApproved() bool {
+	return t.NameStatus == "" || t.NameStatus == NameApproved
+}
+
+// PublicName is the name shown to everyone, an unapproved name is replaced by a neutral placeholder
+func (t *Team) PublicName() string {
+	if t.NameApproved() {
+		return t.Name
+	}
+
+	hex := t.ID.Hex()
+	return "Команда #" + hex[len(hex)-6:]
+}
+
+// NameFor is the name the viewer may see: unapproved names only reach admins and the team itself
+func (t *Team) NameFor(viewer *User) string {
+	if viewer != nil && (viewer.Role == Admin || viewer.Team == t.ID) {
+		return t.Name
+	}
+
+	return t.PublicName()
+}
+
+func (t Team) VisibilityOwner() (bson.ObjectID, bson.ObjectID) {
+	return bson.NilObjectID, t.ID
 }
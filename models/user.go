@@ -18,10 +18,16 @@ type User struct {
 	ID bson.ObjectID `bson:"_id,omitempty" json:"_id"`
 	// Personal data
 	Name      string        `bson:"name" json:"name"`
-	Birthdate time.Time     `bson:"birthdate" json:"birthdate"`
+	Birthdate time.Time     `bson:"birthdate" json:"birthdate" visible:"self,admins"`
 	Role      UserRole      `bson:"role" json:"role"`
 	Team      bson.ObjectID `bson:"team" json:"team"`
+	// When the user joined the current team
+	JoinedAt time.Time `bson:"joined_at,omitempty" json:"joined_at,omitempty"`
 	// TG related
 	Username string `bson:"username" json:"username"`
-	ChatID   int64  `bson:"chat_id" json:"chat_id"`
+	ChatID   int64  `bson:"chat_id" json:"chat_id" visible:"self,admins"`
+}
+
+func (u User) VisibilityOwner() (bson.ObjectID, bson.ObjectID) {
+	return u.ID, u.Team
 }
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Vote is a people's choice vote. Every voter has a limited number of slots and can vote for a team only once.
+type Vote struct {
+	ID        bson.ObjectID `bson:"_id,omitempty" json:"_id"`
+	Voter     bson.ObjectID `bson:"voter" json:"voter" visible:"self"`
+	Team      bson.ObjectID `bson:"team" json:"team"`
+	Slot      int           `bson:"slot" json:"slot"`
+	CreatedAt time.Time     `bson:"created_at" json:"created_at"`
+}
+
+func (v Vote) VisibilityOwner() (bson.ObjectID, bson.ObjectID) {
+	return v.Voter, bson.NilObjectID
+}
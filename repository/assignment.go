@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type AssignmentRepo struct {
+	*GenericRepo[models.Assignment]
+}
+
+func NewAssignmentRepo(database *mongo.Database) *AssignmentRepo {
+	return &AssignmentRepo{
+		GenericRepo: NewGenericRepo[models.Assignment](database, "assignments"),
+	}
+}
+
+// FindBy lists the assignments, a zero judge or team matches any
+func (r *AssignmentRepo) FindBy(ctx context.Context, judge, team bson.ObjectID) ([]models.Assignment, error) {
+	filter := bson.M{}
+	if !judge.IsZero() {
+		filter["judge"] = judge
+	}
+	if !team.IsZero() {
+		filter["team"] = team
+	}
+
+	return FindWithFilter[models.Assignment](ctx, r.Collection, filter)
+}
+
+func (r *AssignmentRepo) IsAssigned(ctx context.Context, judge, team bson.ObjectID) (bool, error) {
+	count, err := r.Collection.CountDocuments(ctx, bson.M{
+		"judge": judge,
+		"team":  team,
+	})
+	return count > 0, err
+}
+
+// ReplaceAll stores the new assignments and then drops the rest.
+// Pairs kept by the new set are never missing in between, which works without transactions.
+func (r *AssignmentRepo) ReplaceAll(ctx context.Context, assignments []models.Assignment) error {
+	generation := bson.NewObjectID()
+
+	if len(assignments) > 0 {
+		writes := make([]mongo.WriteModel, 0, len(assignments))
+		for _, assignment := range assignments {
+			writes = append(writes, mongo.NewUpdateOneModel().SetFilter(bson.M{
+				"judge": assignment.Judge,
+				"team":  assignment.Team,
+			}).SetUpdate(bson.M{
+				"$set": bson.M{
+					"generation": generation,
+				},
+				"$setOnInsert": bson.M{
+					"created_at": assignment.CreatedAt,
+				},
+			}).SetUpsert(true))
+		}
+		if _, err := r.Collection.BulkWrite(ctx, writes); err != nil {
+			return err
+		}
+	}
+
+	_, err := r.Collection.DeleteMany(ctx, bson.M{
+		"generation": bson.M{
+			"$ne": generation,
+		},
+	})
+	return err
+}
+
+// DeleteConflicting removes the assignments of the judge to the teams
+func (r *AssignmentRepo) DeleteConflicting(ctx context.Context, judge bson.ObjectID, teams []bson.ObjectID) error {
+	_, err := r.Collection.DeleteMany(ctx, bson.M{
+		"judge": judge,
+		"team": bson.M{
+			"$in": teams,
+		},
+	})
+	return err
+}
+
+type ConflictRepo struct {
+	*GenericRepo[models.Conflict]
+}
+
+func NewConflictRepo(database *mongo.Database) *ConflictRepo {
+	return &ConflictRepo{
+		GenericRepo: NewGenericRepo[models.Conflict](database, "conflicts"),
+	}
+}
+
+func (r *ConflictRepo) FindByJudge(ctx context.Context, judge bson.ObjectID) ([]models.Conflict, error) {
+	return FindWithFilter[models.Conflict](ctx, r.Collection, bson.M{
+		"judge": judge,
+	})
+}
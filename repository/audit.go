@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type AuditRepo = GenericRepo[models.AuditEntry]
+
+func NewAuditRepo(database *mongo.Database) *AuditRepo {
+	return NewGenericRepo[models.AuditEntry](database, "audit_log")
+}
@@ -1,12 +1,41 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
-type CaseRepo = GenericRepo[models.Case]
+type CaseRepo struct {
+	*GenericRepo[models.Case]
+	database *mongo.Database
+}
 
 func NewCaseRepo(database *mongo.Database) *CaseRepo {
-	return NewGenericRepo[models.Case](database, "cases")
+	return &CaseRepo{
+		GenericRepo: NewGenericRepo[models.Case](database, "cases"),
+		database:    database,
+	}
+}
+
+// Delete removes the case and sends the teams that picked it back to case selection
+func (r *CaseRepo) Delete(ctx context.Context, id bson.ObjectID) error {
+	if err := Delete(ctx, r.Collection, id); err != nil {
+		return err
+	}
+
+	_, err := r.database.Collection("teams").UpdateMany(ctx, bson.M{
+		"case": id,
+	}, bson.M{
+		"$unset": bson.M{
+			"case": "",
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return NewCounterRepo(r.database).Delete(ctx, CaseTeamsCounter(id))
 }
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type CommitRepo struct {
+	*GenericRepo[models.Commit]
+	TruncatedPushes *mongo.Collection
+}
+
+func NewCommitRepo(database *mongo.Database) *CommitRepo {
+	return &CommitRepo{
+		GenericRepo:     NewGenericRepo[models.Commit](database, "commits"),
+		TruncatedPushes: database.Collection("truncated_pushes"),
+	}
+}
+
+// Record stores the commits, skipping the ones the team already pushed to another branch.
+// Returns the number of new commits.
+func (r *CommitRepo) Record(ctx context.Context, commits []models.Commit) (int64, error) {
+	if len(commits) == 0 {
+		return 0, nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(commits))
+	for _, commit := range commits {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"team": commit.Team, "sha": commit.SHA}).
+			SetUpdate(bson.M{"$setOnInsert": commit}).
+			SetUpsert(true))
+	}
+
+	result, err := r.Collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.UpsertedCount, nil
+}
+
+func (r *CommitRepo) FindByTeam(ctx context.Context, teamID bson.ObjectID) ([]models.Commit, error) {
+	var values = []models.Commit{}
+
+	cursor, err := r.Collection.Find(ctx, bson.M{
+		"team": teamID,
+	}, options.Find().SetSort(bson.M{"timestamp": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &values)
+	return values, err
+}
+
+func (r *CommitRepo) RecordTruncated(ctx context.Context, push *models.TruncatedPush) error {
+	_, err := Create(ctx, r.TruncatedPushes, push)
+	return err
+}
+
+func (r *CommitRepo) FindTruncatedByTeam(ctx context.Context, teamID bson.ObjectID) ([]models.TruncatedPush, error) {
+	return FindWithFilter[models.TruncatedPush](ctx, r.TruncatedPushes, bson.M{
+		"team": teamID,
+	})
+}
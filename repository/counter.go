@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// CounterRepo keeps the sizes limited by the settings, so a limit is checked and taken in a single write
+type CounterRepo struct {
+	Collection *mongo.Collection
+}
+
+func NewCounterRepo(database *mongo.Database) *CounterRepo {
+	return &CounterRepo{
+		Collection: database.Collection("counters"),
+	}
+}
+
+const TeamsCounter = "teams"
+
+func MembersCounter(teamID bson.ObjectID) string {
+	return "members:" + teamID.Hex()
+}
+
+func CaseTeamsCounter(caseID bson.ObjectID) string {
+	return "case_teams:" + caseID.Hex()
+}
+
+// Reserve takes a place unless the counter already reached the limit. A limit of 0 means unlimited.
+func (r *CounterRepo) Reserve(ctx context.Context, key string, limit int) (bool, error) {
+	filter := bson.M{
+		"_id": key,
+	}
+	if limit > 0 {
+		filter["count"] = bson.M{
+			"$lt": limit,
+		}
+	}
+
+	_, err := r.Collection.UpdateOne(ctx, filter, bson.M{
+		"$inc": bson.M{
+			"count": 1,
+		},
+	}, options.UpdateOne().SetUpsert(true))
+	// The upsert clashes with the existing counter when it is full
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Release gives a reserved place back
+func (r *CounterRepo) Release(ctx context.Context, key string) error {
+	_, err := r.Collection.UpdateOne(ctx, bson.M{
+		"_id": key,
+		"count": bson.M{
+			"$gt": 0,
+		},
+	}, bson.M{
+		"$inc": bson.M{
+			"count": -1,
+		},
+	})
+	return err
+}
+
+func (r *CounterRepo) Set(ctx context.Context, key string, count int64) error {
+	_, err := r.Collection.UpdateOne(ctx, bson.M{
+		"_id": key,
+	}, bson.M{
+		"$set": bson.M{
+			"count": count,
+		},
+	}, options.UpdateOne().SetUpsert(true))
+	return err
+}
+
+func (r *CounterRepo) Delete(ctx context.Context, key string) error {
+	_, err := r.Collection.DeleteOne(ctx, bson.M{
+		"_id": key,
+	})
+	return err
+}
+
+// Sync recounts everything from the source collections, fixing drift left by interrupted requests
+func (r *CounterRepo) Sync(ctx context.Context, database *mongo.Database) error {
+	teams := database.Collection("teams")
+	teamCount, err := teams.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	if err := r.Set(ctx, TeamsCounter, teamCount); err != nil {
+		return err
+	}
+
+	members, err := countGroups(ctx, database.Collection("users"), "team")
+	if err != nil {
+		return err
+	}
+	if err := r.setGroups(ctx, teams, members, MembersCounter); err != nil {
+		return err
+	}
+
+	caseTeams, err := countGroups(ctx, teams, "case")
+	if err != nil {
+		return err
+	}
+
+	return r.setGroups(ctx, database.Collection("cases"), caseTeams, CaseTeamsCounter)
+}
+
+// setGroups sets a counter for every document of the collection, missing groups are empty
+func (r *CounterRepo) setGroups(ctx context.Context, collection *mongo.Collection, counts map[bson.ObjectID]int64, key func(bson.ObjectID) string) error {
+	var ids []bson.ObjectID
+	if err := collection.Distinct(ctx, "_id", bson.M{}).Decode(&ids); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := r.Set(ctx, key(id), counts[id]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// countGroups counts the documents per value of the field
+func countGroups(ctx context.Context, collection *mongo.Collection, field string) (map[bson.ObjectID]int64, error) {
+	cursor, err := collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{field: bson.M{"$type": "objectId"}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []struct {
+		ID    bson.ObjectID `bson:"_id"`
+		Count int64         `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[bson.ObjectID]int64, len(groups))
+	for _, group := range groups {
+		counts[group.ID] = group.Count
+	}
+
+	return counts, nil
+}
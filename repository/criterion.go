@@ -1,12 +1,37 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
-type CriterionRepo = GenericRepo[models.Criterion]
+type CriterionRepo struct {
+	*GenericRepo[models.Criterion]
+}
 
 func NewCriterionRepo(database *mongo.Database) *CriterionRepo {
-	return NewGenericRepo[models.Criterion](database, "criteria")
+	return &CriterionRepo{
+		GenericRepo: NewGenericRepo[models.Criterion](database, "criteria"),
+	}
+}
+
+// Find returns the criteria in display order
+func (r *CriterionRepo) Find(ctx context.Context) ([]models.Criterion, error) {
+	var values = []models.Criterion{}
+
+	cursor, err := r.Collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{
+		{Key: "order", Value: 1},
+		{Key: "_id", Value: 1},
+	}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &values)
+	return values, err
 }
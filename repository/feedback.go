@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type FeedbackRepo struct {
+	*GenericRepo[models.Feedback]
+}
+
+func NewFeedbackRepo(database *mongo.Database) *FeedbackRepo {
+	return &FeedbackRepo{
+		GenericRepo: NewGenericRepo[models.Feedback](database, "feedback"),
+	}
+}
+
+// Upsert sets the feedback of the judge for the team in the round
+func (r *FeedbackRepo) Upsert(ctx context.Context, feedback *models.Feedback) (*models.Feedback, error) {
+	now := time.Now()
+
+	var got models.Feedback
+	err := r.Collection.FindOneAndUpdate(ctx, roundFilter(feedback.Round, bson.M{
+		"judge": feedback.Judge,
+		"team":  feedback.Team,
+	}), bson.M{
+		"$set": bson.M{
+			"text":       feedback.Text,
+			"signed":     feedback.Signed,
+			"updated_at": now,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": now,
+		},
+	}, options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&got)
+	if err != nil {
+		return nil, err
+	}
+
+	return &got, nil
+}
+
+// FindBy lists the feedback of the round, a zero judge or team matches any
+func (r *FeedbackRepo) FindBy(ctx context.Context, round, judge, team bson.ObjectID) ([]models.Feedback, error) {
+	filter := bson.M{}
+	if !judge.IsZero() {
+		filter["judge"] = judge
+	}
+	if !team.IsZero() {
+		filter["team"] = team
+	}
+
+	return FindWithFilter[models.Feedback](ctx, r.Collection, roundFilter(round, filter))
+}
+
+func (r *FeedbackRepo) DeleteOne(ctx context.Context, round, judge, team bson.ObjectID) (bool, error) {
+	result, err := r.Collection.DeleteOne(ctx, roundFilter(round, bson.M{
+		"judge": judge,
+		"team":  team,
+	}))
+	if err != nil {
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
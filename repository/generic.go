@@ -28,6 +28,18 @@ func (r *GenericRepo[T]) Find(ctx context.Context) ([]T, error) {
 	return Find[T](ctx, r.Collection)
 }
 
+func (r *GenericRepo[T]) FindByIDs(ctx context.Context, ids []bson.ObjectID) ([]T, error) {
+	return FindWithFilter[T](ctx, r.Collection, bson.M{
+		"_id": bson.M{
+			"$in": ids,
+		},
+	})
+}
+
+func (r *GenericRepo[T]) Count(ctx context.Context) (int64, error) {
+	return r.Collection.CountDocuments(ctx, bson.M{})
+}
+
 func (r *GenericRepo[T]) Create(ctx context.Context, value *T) (bson.ObjectID, error) {
 	return Create(ctx, r.Collection, value)
 }
@@ -70,6 +82,10 @@ func FindWithFilter[T any](ctx context.Context, c *mongo.Collection, filter any)
 }
 
 func FindPaged[T any](ctx context.Context, c *mongo.Collection, page, limit int64) ([]T, int64, error) {
+	return FindPagedWithFilter[T](ctx, c, bson.M{}, page, limit)
+}
+
+func FindPagedWithFilter[T any](ctx context.Context, c *mongo.Collection, filter any, page, limit int64) ([]T, int64, error) {
 	var values = []T{}
 
 	// Set pagination options
@@ -80,7 +96,7 @@ func FindPaged[T any](ctx context.Context, c *mongo.Collection, page, limit int6
 	opts.SetSort(bson.M{"created_at": -1})
 
 	// Init a cursor
-	cursor, err := c.Find(ctx, bson.M{}, opts)
+	cursor, err := c.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -93,7 +109,7 @@ func FindPaged[T any](ctx context.Context, c *mongo.Collection, page, limit int6
 	}
 
 	// Get total count
-	count, err := c.CountDocuments(ctx, bson.M{})
+	count, err := c.CountDocuments(ctx, filter)
 
 	return values, count, err
 }
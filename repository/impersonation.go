@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type ImpersonationRepo = GenericRepo[models.ImpersonationGrant]
+
+func NewImpersonationRepo(database *mongo.Database) *ImpersonationRepo {
+	return NewGenericRepo[models.ImpersonationGrant](database, "impersonation_grants")
+}
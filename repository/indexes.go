@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	namespaceNotFound = 26
+	indexNotFound     = 27
+	duplicateKey      = 11000
+)
+
+// EnsureIndexes creates the indexes the repos rely on for consistency
+func EnsureIndexes(ctx context.Context, database *mongo.Database) error {
+	indexes := map[string][]mongo.IndexModel{
+		"submissions": {
+			{
+				Keys:    bson.D{{Key: "team", Value: 1}, {Key: "version", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			// A team has at most one final version
+			{
+				Keys: bson.D{{Key: "team", Value: 1}},
+				Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+					"final": true,
+				}),
+			},
+		},
+		"assignments": {
+			{
+				Keys:    bson.D{{Key: "judge", Value: 1}, {Key: "team", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+		"commits": {
+			{
+				Keys:    bson.D{{Key: "team", Value: 1}, {Key: "sha", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+		"team_name_keys": {
+			{
+				Keys: bson.D{{Key: "team", Value: 1}},
+			},
+		},
+		"scores": {
+			{
+				Keys:    bson.D{{Key: "round", Value: 1}, {Key: "judge", Value: 1}, {Key: "team", Value: 1}, {Key: "criterion", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+		"feedback": {
+			{
+				Keys:    bson.D{{Key: "round", Value: 1}, {Key: "judge", Value: 1}, {Key: "team", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+		"votes": {
+			{
+				Keys:    bson.D{{Key: "voter", Value: 1}, {Key: "slot", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys:    bson.D{{Key: "voter", Value: 1}, {Key: "team", Value: 1}},
+				Options: options.Index().SetUnique(true).SetName(VoterTeamIndex),
+			},
+		},
+		"seeker_profiles": {
+			{
+				Keys:    bson.D{{Key: "user", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+	}
+
+	// Indexes replaced by the ones above
+	obsolete := map[string][]string{
+		"scores": {"judge_1_team_1_criterion_1"},
+		// Name keys are reserved in team_name_keys
+		"teams": {"name_key_1", "pending_name_key_1"},
+		// Renamed to VoterTeamIndex
+		"votes": {"voter_1_team_1"},
+	}
+	for collection, names := range obsolete {
+		for _, name := range names {
+			err := database.Collection(collection).Indexes().DropOne(ctx, name)
+			var commandErr mongo.CommandError
+			if err != nil && !(errors.As(err, &commandErr) && (commandErr.Code == indexNotFound || commandErr.Code == namespaceNotFound)) {
+				return err
+			}
+		}
+	}
+
+	for collection, indexModels := range indexes {
+		if _, err := database.Collection(collection).Indexes().CreateMany(ctx, indexModels); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
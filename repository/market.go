@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type ProfileRepo struct {
+	*GenericRepo[models.SeekerProfile]
+}
+
+func NewProfileRepo(database *mongo.Database) *ProfileRepo {
+	return &ProfileRepo{
+		GenericRepo: NewGenericRepo[models.SeekerProfile](database, "seeker_profiles"),
+	}
+}
+
+func (r *ProfileRepo) GetByUser(ctx context.Context, userID bson.ObjectID) (*models.SeekerProfile, error) {
+	return GetBy[models.SeekerProfile](ctx, r.Collection, "user", userID)
+}
+
+// Upsert keeps a single profile per user
+func (r *ProfileRepo) Upsert(ctx context.Context, profile *models.SeekerProfile) error {
+	_, err := r.Collection.UpdateOne(ctx, bson.M{
+		"user": profile.User,
+	}, bson.M{
+		"$set": bson.M{
+			"skills":         profile.Skills,
+			"preferred_case": profile.PreferredCase,
+			"role":           profile.Role,
+			"about":          profile.About,
+			"updated_at":     profile.UpdatedAt,
+		},
+	}, options.UpdateOne().SetUpsert(true))
+	return err
+}
+
+func (r *ProfileRepo) DeleteByUser(ctx context.Context, userID bson.ObjectID) error {
+	_, err := r.Collection.DeleteOne(ctx, bson.M{
+		"user": userID,
+	})
+	return err
+}
+
+type PositionRepo struct {
+	*GenericRepo[models.OpenPosition]
+}
+
+func NewPositionRepo(database *mongo.Database) *PositionRepo {
+	return &PositionRepo{
+		GenericRepo: NewGenericRepo[models.OpenPosition](database, "open_positions"),
+	}
+}
+
+func (r *PositionRepo) FindByTeam(ctx context.Context, teamID bson.ObjectID) ([]models.OpenPosition, error) {
+	return FindWithFilter[models.OpenPosition](ctx, r.Collection, bson.M{
+		"team": teamID,
+	})
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type RoundRepo struct {
+	*GenericRepo[models.Round]
+	database *mongo.Database
+}
+
+func NewRoundRepo(database *mongo.Database) *RoundRepo {
+	return &RoundRepo{
+		GenericRepo: NewGenericRepo[models.Round](database, "rounds"),
+		database:    database,
+	}
+}
+
+// Find returns the rounds in the order they are held
+func (r *RoundRepo) Find(ctx context.Context) ([]models.Round, error) {
+	var values = []models.Round{}
+
+	cursor, err := r.Collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{
+		{Key: "order", Value: 1},
+		{Key: "_id", Value: 1},
+	}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &values)
+	return values, err
+}
+
+// GetNext returns the round held after the given one
+func (r *RoundRepo) GetNext(ctx context.Context, round *models.Round) (*models.Round, error) {
+	var got models.Round
+	err := r.Collection.FindOne(ctx, bson.M{
+		"order": bson.M{
+			"$gt": round.Order,
+		},
+	}, options.FindOne().SetSort(bson.D{
+		{Key: "order", Value: 1},
+		{Key: "_id", Value: 1},
+	})).Decode(&got)
+	if err != nil {
+		return nil, err
+	}
+
+	return &got, nil
+}
+
+// Delete removes the round together with the scores and feedback given in it
+func (r *RoundRepo) Delete(ctx context.Context, id bson.ObjectID) error {
+	if err := Delete(ctx, r.Collection, id); err != nil {
+		return err
+	}
+
+	for _, collection := range []string{"scores", "feedback"} {
+		_, err := r.database.Collection(collection).DeleteMany(ctx, bson.M{
+			"round": id,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
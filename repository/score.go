@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type ScoreRepo struct {
+	*GenericRepo[models.Score]
+}
+
+func NewScoreRepo(database *mongo.Database) *ScoreRepo {
+	return &ScoreRepo{
+		GenericRepo: NewGenericRepo[models.Score](database, "scores"),
+	}
+}
+
+// Upsert sets the value of the judge for the team and criterion in a single write and records it in the history
+func (r *ScoreRepo) Upsert(ctx context.Context, score *models.Score) (*models.Score, error) {
+	now := time.Now()
+
+	var got models.Score
+	err := r.Collection.FindOneAndUpdate(ctx, roundFilter(score.Round, bson.M{
+		"judge":     score.Judge,
+		"team":      score.Team,
+		"criterion": score.Criterion,
+	}), bson.M{
+		"$set": bson.M{
+			"value":      score.Value,
+			"comment":    score.Comment,
+			"updated_at": now,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": now,
+		},
+		"$push": bson.M{
+			"history": models.ScoreRevision{
+				Value:     score.Value,
+				Comment:   score.Comment,
+				UpdatedAt: now,
+			},
+		},
+	}, options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&got)
+	if err != nil {
+		return nil, err
+	}
+
+	return &got, nil
+}
+
+// FindBy lists the scores of the round, a zero judge or team matches any
+func (r *ScoreRepo) FindBy(ctx context.Context, round, judge, team bson.ObjectID) ([]models.Score, error) {
+	filter := bson.M{}
+	if !judge.IsZero() {
+		filter["judge"] = judge
+	}
+	if !team.IsZero() {
+		filter["team"] = team
+	}
+
+	return FindWithFilter[models.Score](ctx, r.Collection, roundFilter(round, filter))
+}
+
+func (r *ScoreRepo) DeleteOne(ctx context.Context, round, judge, team, criterion bson.ObjectID) (bool, error) {
+	result, err := r.Collection.DeleteOne(ctx, roundFilter(round, bson.M{
+		"judge":     judge,
+		"team":      team,
+		"criterion": criterion,
+	}))
+	if err != nil {
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+// roundFilter limits the filter to the round, the zero round matches scores given outside of rounds
+func roundFilter(round bson.ObjectID, filter bson.M) bson.M {
+	if round.IsZero() {
+		filter["round"] = bson.M{
+			"$exists": false,
+		}
+	} else {
+		filter["round"] = round
+	}
+
+	return filter
+}
+
+// MigrateTeamGrades moves the grades embedded into team documents into the scores collection.
+// Scores which already exist are kept, so the migration can run on every start.
+func (r *ScoreRepo) MigrateTeamGrades(ctx context.Context, database *mongo.Database) (int, error) {
+	teams := database.Collection("teams")
+
+	var legacy []struct {
+		ID     bson.ObjectID                              `bson:"_id"`
+		Grades map[bson.ObjectID]map[bson.ObjectID]uint16 `bson:"grades"`
+	}
+	cursor, err := teams.Find(ctx, bson.M{
+		"grades": bson.M{
+			"$exists": true,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := cursor.All(ctx, &legacy); err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	now := time.Now()
+	for _, team := range legacy {
+		for judge, values := range team.Grades {
+			for criterion, value := range values {
+				_, err := r.Collection.UpdateOne(ctx, roundFilter(bson.NilObjectID, bson.M{
+					"judge":     judge,
+					"team":      team.ID,
+					"criterion": criterion,
+				}), bson.M{
+					"$setOnInsert": models.Score{
+						Judge:     judge,
+						Team:      team.ID,
+						Criterion: criterion,
+						Value:     int(value),
+						CreatedAt: now,
+						UpdatedAt: now,
+						History: []models.ScoreRevision{
+							{Value: int(value), UpdatedAt: now},
+						},
+					},
+				}, options.UpdateOne().SetUpsert(true))
+				if err != nil {
+					return migrated, err
+				}
+				migrated++
+			}
+		}
+
+		_, err := teams.UpdateOne(ctx, bson.M{
+			"_id": team.ID,
+		}, bson.M{
+			"$unset": bson.M{
+				"grades": "",
+			},
+		})
+		if err != nil {
+			return migrated, err
+		}
+	}
+
+	return migrated, nil
+}
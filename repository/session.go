@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type SessionRepo struct {
+	*GenericRepo[models.Session]
+	Revocations *mongo.Collection
+}
+
+func NewSessionRepo(database *mongo.Database) *SessionRepo {
+	return &SessionRepo{
+		GenericRepo: NewGenericRepo[models.Session](database, "sessions"),
+		Revocations: database.Collection("revocations"),
+	}
+}
+
+func (r *SessionRepo) GetByRefreshHash(ctx context.Context, hash string) (*models.Session, error) {
+	return GetBy[models.Session](ctx, r.Collection, "refresh_hash", hash)
+}
+
+func (r *SessionRepo) DeleteByUser(ctx context.Context, userID bson.ObjectID) error {
+	_, err := r.Collection.DeleteMany(ctx, bson.M{
+		"user": userID,
+	})
+	return err
+}
+
+func (r *SessionRepo) Revoke(ctx context.Context, userID bson.ObjectID, at time.Time) error {
+	_, err := r.Revocations.UpdateOne(ctx, bson.M{
+		"_id": userID,
+	}, bson.M{
+		"$set": bson.M{
+			"revoked_at": at,
+		},
+	}, options.UpdateOne().SetUpsert(true))
+	return err
+}
+
+func (r *SessionRepo) GetRevocation(ctx context.Context, userID bson.ObjectID) (*models.Revocation, error) {
+	return GetByID[models.Revocation](ctx, r.Revocations, userID)
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const settingsID = "global"
+
+type SettingsRepo struct {
+	Collection *mongo.Collection
+}
+
+func NewSettingsRepo(database *mongo.Database) *SettingsRepo {
+	return &SettingsRepo{
+		Collection: database.Collection("settings"),
+	}
+}
+
+// Get returns the stored settings or the defaults if nothing was configured yet
+func (r *SettingsRepo) Get(ctx context.Context) (*models.Settings, error) {
+	settings, err := GetBy[models.Settings](ctx, r.Collection, "_id", settingsID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		settings, err = &models.Settings{ID: settingsID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.JudgingState == "" {
+		settings.JudgingState = models.JudgingOpen
+	}
+
+	return settings, nil
+}
+
+// Update sets the fields of the update and removes the unset ones
+func (r *SettingsRepo) Update(ctx context.Context, update any, unset ...string) error {
+	operations := bson.M{
+		"$set": update,
+	}
+	if len(unset) > 0 {
+		fields := bson.M{}
+		for _, field := range unset {
+			fields[field] = ""
+		}
+		operations["$unset"] = fields
+	}
+
+	_, err := r.Collection.UpdateOne(ctx, bson.M{
+		"_id": settingsID,
+	}, operations, options.UpdateOne().SetUpsert(true))
+	return err
+}
+
+// SetJudgingState moves judging to the next state unless another request changed it first
+func (r *SettingsRepo) SetJudgingState(ctx context.Context, current, next models.JudgingState) (bool, error) {
+	states := []any{current}
+	if current == models.JudgingOpen {
+		states = append(states, "", nil)
+	}
+
+	res, err := r.Collection.UpdateOne(ctx, bson.M{
+		"_id": settingsID,
+		"judging_state": bson.M{
+			"$in": states,
+		},
+	}, bson.M{
+		"$set": bson.M{
+			"judging_state": next,
+		},
+	}, options.UpdateOne().SetUpsert(true))
+	// The upsert clashes with the existing document when its state is different
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return res.MatchedCount > 0 || res.UpsertedCount > 0, nil
+}
+
+// MigrateResultsPublished replaces the legacy published flag with the judging state
+func (r *SettingsRepo) MigrateResultsPublished(ctx context.Context) error {
+	_, err := r.Collection.UpdateOne(ctx, bson.M{
+		"_id":               settingsID,
+		"results_published": true,
+	}, bson.M{
+		"$set": bson.M{
+			"judging_state": models.JudgingPublished,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.Collection.UpdateOne(ctx, bson.M{
+		"_id": settingsID,
+		"results_published": bson.M{
+			"$exists": true,
+		},
+	}, bson.M{
+		"$unset": bson.M{
+			"results_published": "",
+		},
+	})
+	return err
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type SubmissionRepo struct {
+	*GenericRepo[models.Submission]
+}
+
+func NewSubmissionRepo(database *mongo.Database) *SubmissionRepo {
+	return &SubmissionRepo{
+		GenericRepo: NewGenericRepo[models.Submission](database, "submissions"),
+	}
+}
+
+func (r *SubmissionRepo) FindByTeam(ctx context.Context, teamID bson.ObjectID) ([]models.Submission, error) {
+	var values = []models.Submission{}
+
+	cursor, err := r.Collection.Find(ctx, bson.M{
+		"team": teamID,
+	}, options.Find().SetSort(bson.M{"version": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &values)
+	return values, err
+}
+
+// GetLatest returns the newest version submitted up to the given moment, zero time means now
+func (r *SubmissionRepo) GetLatest(ctx context.Context, teamID bson.ObjectID, until time.Time) (*models.Submission, error) {
+	filter := bson.M{
+		"team": teamID,
+	}
+	if !until.IsZero() {
+		filter["created_at"] = bson.M{
+			"$lte": until,
+		}
+	}
+
+	var got models.Submission
+	err := r.Collection.FindOne(ctx, filter, options.FindOne().SetSort(bson.M{"version": -1})).Decode(&got)
+	if err != nil {
+		return nil, err
+	}
+
+	return &got, nil
+}
+
+// GetFinal returns the version explicitly marked as final
+func (r *SubmissionRepo) GetFinal(ctx context.Context, teamID bson.ObjectID) (*models.Submission, error) {
+	var got models.Submission
+	err := r.Collection.FindOne(ctx, bson.M{
+		"team":  teamID,
+		"final": true,
+	}).Decode(&got)
+	if err != nil {
+		return nil, err
+	}
+
+	return &got, nil
+}
+
+// MarkFinal makes the version the only final one of the team.
+// The partial unique index rejects a concurrent request marking another version.
+func (r *SubmissionRepo) MarkFinal(ctx context.Context, teamID bson.ObjectID, id bson.ObjectID) error {
+	_, err := r.Collection.UpdateMany(ctx, bson.M{
+		"team":  teamID,
+		"final": true,
+		"_id": bson.M{
+			"$ne": id,
+		},
+	}, bson.M{
+		"$set": bson.M{
+			"final": false,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := r.Collection.UpdateOne(ctx, bson.M{
+		"_id":  id,
+		"team": teamID,
+	}, bson.M{
+		"$set": bson.M{
+			"final": true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// CreateVersion stores the submission as the next version of the team's project
+func (r *SubmissionRepo) CreateVersion(ctx context.Context, submission *models.Submission) (bson.ObjectID, error) {
+	for range 3 {
+		latest, err := r.GetLatest(ctx, submission.Team, time.Time{})
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			submission.Version = 1
+		} else if err != nil {
+			return bson.NilObjectID, err
+		} else {
+			submission.Version = latest.Version + 1
+		}
+
+		// The unique (team, version) index rejects concurrent submissions
+		id, err := r.Create(ctx, submission)
+		if !mongo.IsDuplicateKeyError(err) {
+			return id, err
+		}
+	}
+
+	return bson.NilObjectID, errors.New("too many concurrent submissions")
+}
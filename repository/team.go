@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/SomeSuperCoder/global-chat/internal"
 	"github.com/SomeSuperCoder/global-chat/models"
@@ -13,12 +15,15 @@ type TeamRepo struct {
 	*GenericRepo[models.Team]
 	database *mongo.Database
 	Users    *mongo.Collection
+	// Name keys reserved by the names and pending renames, the _id index keeps them unique
+	NameKeys *mongo.Collection
 }
 
 func NewTeamRepo(database *mongo.Database) *TeamRepo {
 	return &TeamRepo{
 		database:    database,
 		Users:       database.Collection("users"),
+		NameKeys:    database.Collection("team_name_keys"),
 		GenericRepo: NewGenericRepo[models.Team](database, "teams"),
 	}
 }
@@ -29,17 +34,244 @@ func (r *TeamRepo) GetMembers(ctx context.Context, id bson.ObjectID) ([]models.U
 	})
 }
 
+func (r *TeamRepo) FindByCase(ctx context.Context, caseID bson.ObjectID) ([]models.Team, error) {
+	return FindWithFilter[models.Team](ctx, r.Collection, bson.M{
+		"case": caseID,
+	})
+}
+
+// SetCase moves the team from one case to another unless another request changed the case first.
+// A zero case means no case.
+func (r *TeamRepo) SetCase(ctx context.Context, id bson.ObjectID, from, to bson.ObjectID) (bool, error) {
+	filter := bson.M{
+		"_id":  id,
+		"case": from,
+	}
+	if from.IsZero() {
+		filter["case"] = bson.M{
+			"$in": []any{nil, bson.NilObjectID},
+		}
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"case": to,
+		},
+	}
+	if to.IsZero() {
+		update = bson.M{
+			"$unset": bson.M{
+				"case": "",
+			},
+		}
+	}
+
+	res, err := r.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
+// FindPagedPublic skips the teams whose name has not been approved
+func (r *TeamRepo) FindPagedPublic(ctx context.Context, page, limit int64) ([]models.Team, int64, error) {
+	return FindPagedWithFilter[models.Team](ctx, r.Collection, bson.M{
+		"name_status": bson.M{
+			"$nin": []models.NameStatus{models.NamePending, models.NameRejected},
+		},
+	}, page, limit)
+}
+
+// FindPendingNames returns the new and renamed teams waiting for review
+func (r *TeamRepo) FindPendingNames(ctx context.Context) ([]models.Team, error) {
+	return FindWithFilter[models.Team](ctx, r.Collection, bson.M{
+		"$or": []bson.M{
+			{"name_status": models.NamePending},
+			{"pending_name": bson.M{"$exists": true}},
+		},
+	})
+}
+
+// IsNameTaken checks the key against the names and pending renames of the other teams
+func (r *TeamRepo) IsNameTaken(ctx context.Context, key string, except bson.ObjectID) (bool, error) {
+	count, err := r.NameKeys.CountDocuments(ctx, bson.M{
+		"_id": key,
+		"team": bson.M{
+			"$ne": except,
+		},
+	})
+	return count > 0, err
+}
+
+// ReserveNameKey claims the key for the team. Reserving a key the team already holds succeeds.
+func (r *TeamRepo) ReserveNameKey(ctx context.Context, key string, teamID bson.ObjectID) (bool, error) {
+	_, err := r.NameKeys.InsertOne(ctx, models.TeamNameKey{
+		Key:  key,
+		Team: teamID,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		taken, err := r.IsNameTaken(ctx, key, teamID)
+		return !taken, err
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ReleaseNameKey frees the key if the team still holds it
+func (r *TeamRepo) ReleaseNameKey(ctx context.Context, key string, teamID bson.ObjectID) error {
+	if key == "" {
+		return nil
+	}
+
+	_, err := r.NameKeys.DeleteOne(ctx, bson.M{
+		"_id":  key,
+		"team": teamID,
+	})
+	return err
+}
+
+func (r *TeamRepo) UnsetPendingName(ctx context.Context, id bson.ObjectID, update any) error {
+	_, err := r.Collection.UpdateOne(ctx, bson.M{
+		"_id": id,
+	}, bson.M{
+		"$set": update,
+		"$unset": bson.M{
+			"pending_name":     "",
+			"pending_name_key": "",
+		},
+	})
+	return err
+}
+
+// BackfillNameKeys reserves the keys of the existing names and pending renames and sets the name key
+// of teams created before names were moderated.
+// Teams clashing with an earlier one are left without a key and returned, so an admin can rename them.
+func (r *TeamRepo) BackfillNameKeys(ctx context.Context, nameKey func(string) string) ([]models.Team, error) {
+	teams, err := r.Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clashing := []models.Team{}
+	for _, team := range teams {
+		key := team.NameKey
+		if key == "" {
+			key = nameKey(team.Name)
+		}
+
+		reserved, err := r.ReserveNameKey(ctx, key, team.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !reserved {
+			clashing = append(clashing, team)
+			continue
+		}
+
+		if team.NameKey == "" {
+			if err := r.Update(ctx, team.ID, bson.M{"name_key": key}); err != nil {
+				return nil, err
+			}
+		}
+
+		if team.PendingNameKey != "" {
+			reserved, err := r.ReserveNameKey(ctx, team.PendingNameKey, team.ID)
+			if err != nil {
+				return nil, err
+			}
+			if !reserved {
+				// The rename can't be approved anymore, drop it
+				if err := r.UnsetPendingName(ctx, team.ID, bson.M{}); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return clashing, nil
+}
+
+// MarkResultsNotified records that the team got its results, false if it already did
+func (r *TeamRepo) MarkResultsNotified(ctx context.Context, id bson.ObjectID, at time.Time) (bool, error) {
+	res, err := r.Collection.UpdateOne(ctx, bson.M{
+		"_id": id,
+		"results_notified_at": bson.M{
+			"$exists": false,
+		},
+	}, bson.M{
+		"$set": bson.M{
+			"results_notified_at": at,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return res.ModifiedCount > 0, nil
+}
+
 func (r *TeamRepo) Delete(ctx context.Context, id bson.ObjectID) error {
-	if err := Delete(ctx, r.Collection, id); err != nil {
+	var deleted models.Team
+	err := r.Collection.FindOneAndDelete(ctx, bson.M{
+		"_id": id,
+	}).Decode(&deleted)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+
+	// Only the request that actually removed the team gives its places back
+	counters := NewCounterRepo(r.database)
+	if err == nil {
+		if err := counters.Release(ctx, TeamsCounter); err != nil {
+			return err
+		}
+		if !deleted.Case.IsZero() {
+			if err := counters.Release(ctx, CaseTeamsCounter(deleted.Case)); err != nil {
+				return err
+			}
+		}
+	}
+	if err := counters.Delete(ctx, MembersCounter(id)); err != nil {
 		return err
 	}
 
-	_, err := r.Users.UpdateMany(ctx, bson.M{
+	_, err = r.NameKeys.DeleteMany(ctx, bson.M{
+		"team": id,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.Users.UpdateMany(ctx, bson.M{
 		"team": id,
 	}, bson.M{
 		"$set": bson.M{
 			"team": internal.UndefinedObjectID,
 		},
 	})
+	if err != nil {
+		return err
+	}
+
+	for _, collection := range []string{"assignments", "scores", "feedback", "votes"} {
+		_, err = r.database.Collection(collection).DeleteMany(ctx, bson.M{
+			"team": id,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = r.database.Collection("rounds").UpdateMany(ctx, bson.M{
+		"teams": id,
+	}, bson.M{
+		"$pull": bson.M{
+			"teams": id,
+		},
+	})
 	return err
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
@@ -20,3 +21,9 @@ func NewUserRepo(database *mongo.Database) *UserRepo {
 func (r *UserRepo) GetByUsername(ctx context.Context, username string) (*models.User, error) {
 	return GetBy[models.User](ctx, r.Collection, "username", username)
 }
+
+func (r *UserRepo) FindByRole(ctx context.Context, role models.UserRole) ([]models.User, error) {
+	return FindWithFilter[models.User](ctx, r.Collection, bson.M{
+		"role": role,
+	})
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/SomeSuperCoder/global-chat/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var ErrAlreadyVoted = errors.New("Already voted for this team")
+var ErrNoVotesLeft = errors.New("No votes left")
+
+type VoteRepo struct {
+	*GenericRepo[models.Vote]
+}
+
+func NewVoteRepo(database *mongo.Database) *VoteRepo {
+	return &VoteRepo{
+		GenericRepo: NewGenericRepo[models.Vote](database, "votes"),
+	}
+}
+
+// Find returns the votes in the order they were cast
+func (r *VoteRepo) Find(ctx context.Context) ([]models.Vote, error) {
+	var values = []models.Vote{}
+
+	cursor, err := r.Collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{
+		{Key: "created_at", Value: 1},
+		{Key: "_id", Value: 1},
+	}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &values)
+	return values, err
+}
+
+func (r *VoteRepo) FindByVoter(ctx context.Context, voter bson.ObjectID) ([]models.Vote, error) {
+	return FindWithFilter[models.Vote](ctx, r.Collection, bson.M{
+		"voter": voter,
+	})
+}
+
+// VoterTeamIndex is the unique (voter, team) index, its name tells a second vote from a taken slot
+const VoterTeamIndex = "voter_team"
+
+// Cast stores the vote in the first free slot of the voter. The unique (voter, slot) index
+// enforces the limit and the unique (voter, team) index prevents voting twice, even concurrently.
+func (r *VoteRepo) Cast(ctx context.Context, vote *models.Vote, limit int) (*models.Vote, error) {
+	votes, err := r.FindByVoter(ctx, vote.Voter)
+	if err != nil {
+		return nil, err
+	}
+	taken := make(map[int]bool, len(votes))
+	for _, existing := range votes {
+		if existing.Team == vote.Team {
+			return nil, ErrAlreadyVoted
+		}
+		taken[existing.Slot] = true
+	}
+
+	for slot := range limit {
+		if taken[slot] {
+			continue
+		}
+
+		vote.Slot = slot
+		id, err := r.Create(ctx, vote)
+		if err == nil {
+			vote.ID = id
+			return vote, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+		if isDuplicateIn(err, VoterTeamIndex) {
+			return nil, ErrAlreadyVoted
+		}
+	}
+
+	return nil, ErrNoVotesLeft
+}
+
+// isDuplicateIn reports whether the write clashed with the named unique index
+func isDuplicateIn(err error, index string) bool {
+	var writeErr mongo.WriteException
+	if !errors.As(err, &writeErr) {
+		return false
+	}
+
+	for _, e := range writeErr.WriteErrors {
+		if e.HasErrorCode(duplicateKey) && strings.Contains(e.Message, "index: "+index+" ") {
+			return true
+		}
+	}
+
+	return false
+}
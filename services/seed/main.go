@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SomeSuperCoder/global-chat/internal/auth"
+	"github.com/SomeSuperCoder/global-chat/internal/fixtures"
+	"github.com/SomeSuperCoder/global-chat/utils"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Seeds the test fixtures and prints a ready-to-use X-Test-Identity header for each user
+func main() {
+	ctx := context.Background()
+
+	// Load .env
+	err := godotenv.Load()
+	utils.CheckErrorDeadly(err, "Failed to load .env")
+	utils.CheckErrorDeadly(auth.CheckTestMode(), "Refusing to seed")
+	if !auth.TestModeEnabled() {
+		logrus.Fatal("Test mode is disabled: set TEST_AUTH_KEY and APP_ENV=test")
+	}
+
+	// Connect to MongoDB
+	connectionString := "mongodb://localhost:27017"
+	client, err := mongo.Connect(options.Client().ApplyURI(connectionString))
+	utils.CheckErrorDeadly(err, "Failed to connect to MongoDB")
+	defer client.Disconnect(ctx)
+
+	// Never write fixtures into the production database
+	err = fixtures.Seed(ctx, client.Database(auth.TestDatabase))
+	utils.CheckErrorDeadly(err, "Failed to seed fixtures")
+
+	for _, user := range fixtures.Users() {
+		header, err := auth.IssueTestIdentity(&auth.TestIdentity{UserID: user.ID})
+		utils.CheckErrorDeadly(err, "Failed to issue test identity")
+		fmt.Printf("%s (%s)\n\t%s: %s\n", user.Username, user.ID.Hex(), auth.TestIdentityHeader, header)
+	}
+}
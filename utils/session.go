@@ -10,15 +10,11 @@ import (
 	"github.com/SomeSuperCoder/global-chat/models"
 	"github.com/SomeSuperCoder/global-chat/repository"
 	initdata "github.com/telegram-mini-apps/init-data-golang"
-	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 var AuthError = errors.New("Unauthorized")
 
-func Authorize(r *http.Request, db *mongo.Database) (*models.User, error, int) {
-	// Init repo
-	repo := repository.NewUserRepo(db)
-
+func Authorize(r *http.Request, repo *repository.UserRepo) (*models.User, error, int) {
 	// Load init data from header
 	initData := r.Header.Get("TG-Init-Data")
 